@@ -0,0 +1,58 @@
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+type discardWriter struct{ header http.Header }
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+func newAllocMux() *mux.Mux {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+		_ = mux.Vars(r).Get("id")
+	})
+	m.Handle(mux.GET, "/", h)
+	m.Handle(mux.GET, "/about/", h)
+	var api = m.Handle(mux.ANY, "/api/", nil)
+	api.Handle(mux.GET, "/users/", h)
+	api.Handle(mux.GET, "/users/<<id:int>>/", h)
+	api.Handle(mux.GET, "/users/<<id:int>>/posts/<<slug>>/", h)
+	m.Handle(mux.GET, "/static/<<path...>>", h)
+	m.Compile()
+	return m
+}
+
+func benchmarkServe(b *testing.B, path string) {
+	var m = newAllocMux()
+	var req = httptest.NewRequest(mux.GET, path, nil)
+	var w = &discardWriter{header: make(http.Header)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkServeStatic(b *testing.B)   { benchmarkServe(b, "/api/users/") }
+func BenchmarkServeVariable(b *testing.B) { benchmarkServe(b, "/api/users/42/") }
+func BenchmarkServeTwoVars(b *testing.B)  { benchmarkServe(b, "/api/users/42/posts/hello/") }
+func BenchmarkServeGlob(b *testing.B)     { benchmarkServe(b, "/static/css/site/main.css") }
+func BenchmarkServeNotFound(b *testing.B) { benchmarkServe(b, "/api/missing/") }
+
+func BenchmarkMatchVariable(b *testing.B) {
+	var m = newAllocMux()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Match(mux.GET, "/api/users/42/posts/hello/")
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !race
+// +build !race
+
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+// The race detector drops pooled values at random, so this only runs without it.
+func TestServeAllocations(t *testing.T) {
+	var m = newAllocMux()
+	var w = &discardWriter{header: make(http.Header)}
+
+	for _, path := range []string{"/api/users/", "/api/users/42/", "/api/users/42/posts/hello/", "/static/css/site/main.css"} {
+		var req = httptest.NewRequest(mux.GET, path, nil)
+		m.ServeHTTP(w, req)
+
+		// The request is copied to store the match in its context, which allocates
+		// the copy, the context and the match. The variables are copied out of the
+		// pooled state into a map and a single slice for their values.
+		var allocs = testing.AllocsPerRun(100, func() {
+			m.ServeHTTP(w, req)
+		})
+		if allocs > 6 {
+			t.Errorf("Expected serving %s to allocate at most 6 times, got %v", path, allocs)
+		}
+	}
+}
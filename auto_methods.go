@@ -0,0 +1,133 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// matchRequest matches the method, host and path to a route.
+//
+// If the request is not nil, the matchers of routes are checked against it.
+//
+// If the path is not in its canonical form, the returned fallback handler
+// redirects to the canonical path.
+//
+// When no route matches the method, but routes for the path do exist, the automatic
+// HEAD and OPTIONS handling is applied if enabled. If that does not produce a route
+// either, the returned fallback handler responds with a 405.
+// These fallback handlers run through the middleware of the mux.
+//
+// The leaf of the matched route holds the route and its handler, wrapped in its middleware chain.
+// The variables are stored in the state if it reuses them.
+//
+// If nothing matched the path, all return values are empty.
+func (r *Mux) matchRequest(st *matchState, method, host, path string, req *http.Request) (leaf *treeLeaf, vars Variables, head bool, fallback http.HandlerFunc) {
+	if r.CleanPath {
+		if cleaned := CleanPath(path); cleaned != path {
+			return nil, nil, false, redirectHandler(cleaned)
+		}
+	}
+
+	var in = r.newMatchInput(st, method, host, path, req)
+	var tree = r.compiled()
+	c, vars, allowed := tree.lookup(st, in)
+	if c == nil && r.AutoHead && method == HEAD && slices.Contains(allowed, GET) {
+		in.method = GET
+		c, vars, _ = tree.lookup(st, in)
+		head = c != nil
+	}
+
+	if c != nil {
+		if canonical, ok := r.canonicalPath(path, c); ok {
+			return nil, nil, false, redirectHandler(canonical)
+		}
+		return c.leaf, vars, head, nil
+	}
+
+	if len(allowed) == 0 {
+		return nil, nil, false, nil
+	}
+
+	allowed = r.allowedMethods(allowed)
+	if r.AutoOptions && method == OPTIONS {
+		return nil, nil, false, tree.wrap(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+
+	return nil, nil, false, tree.wrap(func(w http.ResponseWriter, req *http.Request) {
+		r.MethodNotAllowed(w, req, allowed)
+	})
+}
+
+// wrap wraps the fallback response in the middleware of the mux,
+// so middleware like CORS sees the automatic OPTIONS and 405 responses.
+func (t *routeTree) wrap(fallback http.HandlerFunc) http.HandlerFunc {
+	if len(t.middleware) == 0 {
+		return fallback
+	}
+	var handler Handler = fallback
+	for i := len(t.middleware) - 1; i >= 0; i-- {
+		handler = t.middleware[i](handler)
+	}
+	return handler.ServeHTTP
+}
+
+// allowedMethods adds the automatically handled methods to the allowed methods.
+func (r *Mux) allowedMethods(allowed []string) []string {
+	if r.AutoHead && slices.Contains(allowed, GET) && !slices.Contains(allowed, HEAD) {
+		allowed = append(allowed, HEAD)
+	}
+	if r.AutoOptions && !slices.Contains(allowed, OPTIONS) {
+		allowed = append(allowed, OPTIONS)
+	}
+	slices.Sort(allowed)
+	return allowed
+}
+
+// headResponseWriter discards the body written by a GET handler
+// serving a HEAD request, while keeping the Content-Length.
+//
+// Writing the header is delayed until the handler has returned,
+// so the length of the discarded body is known.
+type headResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int
+}
+
+func newHeadResponseWriter(w http.ResponseWriter) *headResponseWriter {
+	return &headResponseWriter{ResponseWriter: w}
+}
+
+func (w *headResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.written += len(b)
+	return len(b), nil
+}
+
+// finish writes the header to the underlying response writer.
+func (w *headResponseWriter) finish() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	var header = w.ResponseWriter.Header()
+	if header.Get("Content-Length") == "" && w.written > 0 {
+		header.Set("Content-Length", strconv.Itoa(w.written))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
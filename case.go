@@ -0,0 +1,24 @@
+package mux
+
+// IgnoreCase sets whether static text in the path of the route and
+// its children is matched case-insensitively.
+//
+// Variables keep the case of the request path.
+func (r *Route) IgnoreCase(b bool) *Route {
+	defer r.lock()()
+	r.CaseInsensitive = b
+	if r.ParentMux != nil {
+		r.ParentMux.invalidate()
+	}
+	return r
+}
+
+// ignoresCase reports whether the route or any of its parents matches case-insensitively.
+func (r *Route) ignoresCase() bool {
+	for curr := r; curr != nil; curr = curr.Parent {
+		if curr.CaseInsensitive {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func newCaseMux() *mux.Mux {
+	var m = mux.New()
+	var write = func(name string) mux.Handler {
+		return mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %v", name, mux.Vars(r))
+		})
+	}
+	m.Handle(mux.GET, "/about/team/", write("team"))
+	m.Handle(mux.GET, "/About/", write("About"))
+	m.Handle(mux.GET, "/about/", write("about"))
+	m.Handle(mux.GET, "/posts/<<slug>>/", write("post"))
+	m.Handle(mux.GET, "/files/<<name>>.pdf/", write("pdf"))
+	m.Handle(mux.GET, "/static/*", write("static"))
+	return m
+}
+
+func TestIgnoreCase(t *testing.T) {
+	var m = newCaseMux()
+	m.IgnoreCase = true
+
+	var tests = []struct {
+		path     string
+		expected string
+	}{
+		{"/about/", "about map[]"},
+		{"/About/", "About map[]"},
+		{"/ABOUT/", "About map[]"},
+		{"/About/Team/", "team map[]"},
+		{"/POSTS/Hello-World/", "post map[slug:[Hello-World]]"},
+		{"/Files/Report.PDF/", "pdf map[name:[Report]]"},
+		{"/STATIC/Css/App.css", "static map[*:[Css App.css]]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(mux.GET, test.path, nil))
+			if w.Code != http.StatusOK || w.Body.String() != test.expected {
+				t.Fatalf("Expected %q, got %d %q", test.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	m.IgnoreCase = false
+	if route, _ := m.Match(mux.GET, "/About/Team/"); route != nil {
+		t.Fatalf("Expected no match when case is significant, got %v", route)
+	}
+}
+
+func TestIgnoreCaseSubtree(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var marketing = m.Handle(mux.ANY, "/marketing/", nil).IgnoreCase(true)
+	marketing.Handle(mux.GET, "/pricing/", h, "pricing")
+	m.Handle(mux.GET, "/api/users/", h, "users")
+
+	if route, _ := m.Match(mux.GET, "/Marketing/PRICING/"); route == nil || route.Name != "pricing" {
+		t.Fatalf("Expected the pricing route, got %v", route)
+	}
+	if route, _ := m.Match(mux.GET, "/API/users/"); route != nil {
+		t.Fatalf("Expected no match outside the case-insensitive subtree, got %v", route)
+	}
+}
+
+func TestCaseRedirect(t *testing.T) {
+	var m = newCaseMux()
+	m.IgnoreCase = true
+	m.CaseRedirect = true
+
+	var tests = []struct {
+		target   string
+		status   int
+		location string
+	}{
+		{"/about/", http.StatusOK, ""},
+		{"/About/", http.StatusOK, ""},
+		{"/ABOUT/", http.StatusMovedPermanently, "/about/"},
+		{"/About/TEAM/", http.StatusMovedPermanently, "/about/team/"},
+		{"/POSTS/Hello-World/?page=2", http.StatusMovedPermanently, "/posts/Hello-World/?page=2"},
+		{"/files/Report.PDF/", http.StatusMovedPermanently, "/files/Report.pdf/"},
+		{"/Static/Css/App.css", http.StatusMovedPermanently, "/static/Css/App.css"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.target, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(mux.GET, test.target, nil))
+			if w.Code != test.status {
+				t.Fatalf("Expected status %d, got %d", test.status, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != test.location {
+				t.Fatalf("Expected a redirect to %q, got %q", test.location, location)
+			}
+		})
+	}
+
+	// A route written in mixed case is redirected to, and served for, its lowercase path.
+	var pricing = mux.New()
+	pricing.IgnoreCase = true
+	pricing.CaseRedirect = true
+	pricing.Handle(mux.GET, "/Pricing/<<Plan>>/", mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {}))
+	var rec = httptest.NewRecorder()
+	pricing.ServeHTTP(rec, httptest.NewRequest(mux.GET, "/PRICING/Pro/", nil))
+	if location := rec.Header().Get("Location"); location != "/pricing/Pro/" {
+		t.Fatalf("Expected a redirect to /pricing/Pro/, got %q", location)
+	}
+	rec = httptest.NewRecorder()
+	pricing.ServeHTTP(rec, httptest.NewRequest(mux.GET, "/pricing/Pro/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the lowercase path to be served, got status %d", rec.Code)
+	}
+
+	m.TrailingSlash = mux.TrailingSlashRedirectRemove
+	var w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.GET, "/About/Team/", nil))
+	if location := w.Header().Get("Location"); location != "/about/team" {
+		t.Fatalf("Expected a single redirect to /about/team, got %q", location)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import "net/http"
+
+// Compile builds the route tree and composes the middleware chains of all routes,
+// which is otherwise done on the first request after the routes or middleware changed.
+//
+// Call it after registering routes to keep the first request fast,
+// and to surface problems with the routes at startup.
+func (r *Mux) Compile() {
+	r.compiled()
+}
+
+// chain returns the handler of the route wrapped in its middleware, in the order
+// they run: the pre-middleware, the middleware of the mux and the middleware of the route.
+//
+// The chain is composed once for every route table, see Mux.Compile.
+// Handlers which implement BindableHandler are bound for each request inside the chain,
+// after the middleware ran instead of before it, so the chain does not have to be composed for every request.
+func (r *Route) chain(middleware []Middleware) Handler {
+	var handler = r.Handler
+	if bindable, ok := handler.(BindableHandler); ok {
+		handler = &boundHandler{route: r, handler: bindable}
+	}
+
+	// Do not run middleware if disabled.
+	//lint:ignore S1002 Extra verbose to make it more clear.
+	if r.DisabledMiddleware == false {
+		for i := len(r.Middleware) - 1; i >= 0; i-- {
+			handler = r.Middleware[i](handler)
+		}
+
+		for i := len(middleware) - 1; i >= 0; i-- {
+			handler = middleware[i](handler)
+		}
+
+		for i := len(r.PreMiddleware) - 1; i >= 0; i-- {
+			handler = r.PreMiddleware[i](handler)
+		}
+	}
+
+	return handler
+}
+
+// boundHandler binds a BindableHandler to the request it serves,
+// with the variables stored in the context of the request.
+type boundHandler struct {
+	route   *Route
+	handler BindableHandler
+}
+
+func (h *boundHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.handler.Bind(req, h.route, Vars(req)).ServeHTTP(w, req)
+}
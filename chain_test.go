@@ -0,0 +1,133 @@
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+type bindableHandler struct {
+	bound int
+}
+
+func (h *bindableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("unbound"))
+}
+
+func (h *bindableHandler) Bind(r *http.Request, rt *mux.Route, vars mux.Variables) mux.Handler {
+	h.bound++
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rt.Name + ":" + vars.Get("id") + ":" + r.Header.Get("X-Trace")))
+	})
+}
+
+func TestMiddlewareChain(t *testing.T) {
+	var m = mux.New()
+	var composed = make(map[string]int)
+	var named = func(name string) mux.Middleware {
+		return func(next mux.Handler) mux.Handler {
+			composed[name]++
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.Header.Set("X-Trace", r.Header.Get("X-Trace")+name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	var bindable = &bindableHandler{}
+	var route = m.Handle(mux.GET, "/users/<<id:int>>/", bindable, "user")
+	route.Use(named("r"))
+	route.Preprocess(named("p"))
+	m.Use(named("m"))
+	m.Compile()
+
+	var serve = func() string {
+		var rec = httptest.NewRecorder()
+		m.ServeHTTP(rec, httptest.NewRequest(mux.GET, "/users/42/", nil))
+		return rec.Body.String()
+	}
+
+	for i := 0; i < 3; i++ {
+		if body := serve(); body != "user:42:pmr" {
+			t.Fatalf("Expected the chain to run in order, got %q", body)
+		}
+	}
+	if composed["p"] != 1 || composed["m"] != 1 || composed["r"] != 1 {
+		t.Fatalf("Expected the chain to be composed once, got %v", composed)
+	}
+	if bindable.bound != 3 {
+		t.Fatalf("Expected the handler to be bound for every request, got %d", bindable.bound)
+	}
+
+	// Changing the middleware composes the chain again.
+	m.Use(named("x"))
+	if body := serve(); body != "user:42:pmxr" || composed["m"] != 2 {
+		t.Fatalf("Expected the new middleware to run, got %q %v", body, composed)
+	}
+
+	route.RunsMiddleware(false)
+	if body := serve(); body != "user:42:" {
+		t.Fatalf("Expected the middleware to be disabled, got %q", body)
+	}
+
+	var h, _, ok = m.Resolve(mux.GET, "/users/7/")
+	var rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(mux.GET, "/", nil))
+	if !ok || rec.Body.String() != "user:7:" {
+		t.Fatalf("Expected Resolve to serve the bound handler, got %q", rec.Body.String())
+	}
+}
+
+type traceBinder struct{}
+
+func (traceBinder) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func (traceBinder) Bind(r *http.Request, rt *mux.Route, vars mux.Variables) mux.Handler {
+	var trace = r.Header.Get("X-Trace")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(trace))
+	})
+}
+
+func TestBindAfterMiddleware(t *testing.T) {
+	var m = mux.New()
+	m.Use(func(next mux.Handler) mux.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set("X-Trace", "m")
+			next.ServeHTTP(w, r)
+		})
+	})
+	m.Handle(mux.GET, "/", traceBinder{})
+
+	var rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(mux.GET, "/", nil))
+	if rec.Body.String() != "m" {
+		t.Fatalf("Expected the handler to be bound after the middleware ran, got %q", rec.Body.String())
+	}
+}
+
+func BenchmarkServeHTTPMiddleware(b *testing.B) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var passthrough = func(next mux.Handler) mux.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+		})
+	}
+	m.Use(passthrough, passthrough)
+	var api = m.Handle(mux.ANY, "/api/", nil)
+	api.Use(passthrough, passthrough)
+	api.Handle(mux.GET, "/users/<<id:int>>/", h).Use(passthrough)
+	m.Compile()
+
+	var req = httptest.NewRequest(mux.GET, "/api/users/42/", nil)
+	var rec = httptest.NewRecorder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.ServeHTTP(rec, req)
+	}
+}
@@ -5,10 +5,8 @@ import (
 	"net/http"
 )
 
-var (
-	routeContextKey = ContextKey{"mux.route"}
-	varsContextKey  = ContextKey{"mux.variables"}
-)
+// The key is stored as an interface once, so looking it up does not allocate.
+var matchContextKey any = ContextKey{"mux.match"}
 
 // Exported ContextKey struct allows third party packages
 // to work with the context more freely.
@@ -16,26 +14,70 @@ type ContextKey struct {
 	K string
 }
 
+// matchRecord is stored in the context of a request which matched a route,
+// it holds the route, its pattern and the variables of the request in a single value.
+//
+// The record is never changed once it is stored.
+type matchRecord struct {
+	route   *Route
+	pattern string
+	vars    Variables
+}
+
+func matchFromContext(ctx context.Context) *matchRecord {
+	m, _ := ctx.Value(matchContextKey).(*matchRecord)
+	return m
+}
+
+// withMatch returns a copy of the context with a copy of its match record, changed by fn.
+func withMatch(ctx context.Context, fn func(m *matchRecord)) context.Context {
+	var m matchRecord
+	if prev := matchFromContext(ctx); prev != nil {
+		m = *prev
+	}
+	fn(&m)
+	return context.WithValue(ctx, matchContextKey, &m)
+}
+
 func ContextWithRoute(ctx context.Context, route *Route) context.Context {
-	return context.WithValue(ctx, routeContextKey, route)
+	return withMatch(ctx, func(m *matchRecord) {
+		m.route = route
+		m.pattern = ""
+		if route != nil && route.Path != nil {
+			m.pattern = route.Path.String()
+		}
+	})
 }
 
+// RouteFromContext returns the route which matched the request of the context.
+//
+// The route stays available in contexts which outlive the request, I.E. through context.WithoutCancel.
 func RouteFromContext(ctx context.Context) *Route {
-	route, ok := ctx.Value(routeContextKey).(*Route)
-	if !ok {
-		return nil
+	if m := matchFromContext(ctx); m != nil {
+		return m.route
+	}
+	return nil
+}
+
+// RoutePattern returns the full pattern of the route which matched the request,
+// including the patterns of its parents, I.E. `/users/<<id:int>>`.
+func RoutePattern(r *http.Request) string {
+	if m := matchFromContext(r.Context()); m != nil {
+		return m.pattern
 	}
-	return route
+	return ""
 }
 
 func SetContextVars(r *http.Request, v Variables) *http.Request {
-	return r.WithContext(context.WithValue(r.Context(), varsContextKey, v))
+	return r.WithContext(withMatch(r.Context(), func(m *matchRecord) {
+		m.vars = v
+	}))
 }
 
+// Vars returns the variables of the request.
 func Vars(r *http.Request) Variables {
-	var v = r.Context().Value(varsContextKey)
-	if v == nil {
-		return nil
+	if m := matchFromContext(r.Context()); m != nil {
+		return m.vars
 	}
-	return v.(Variables)
+	return nil
 }
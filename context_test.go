@@ -0,0 +1,82 @@
+package mux_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestMatchContext(t *testing.T) {
+	var m = mux.New()
+	var want = "42"
+	var api = m.Handle(mux.ANY, "/api/", nil, "api")
+	api.HandleFunc(mux.GET, "/users/<<id:int>>/", func(w http.ResponseWriter, r *http.Request) {
+		if mux.RouteFromContext(r.Context()).Name != "user" || mux.RoutePattern(r) != "/api/users/<<id:int>>" || mux.Vars(r).Get("id") != want {
+			t.Errorf("Unexpected match %v %q %v", mux.RouteFromContext(r.Context()), mux.RoutePattern(r), mux.Vars(r))
+		}
+
+		// Changing one value of the match keeps the others.
+		r = mux.SetContextVars(r, mux.Variables{"id": {"7"}})
+		if mux.Vars(r).Get("id") != "7" || mux.RouteFromContext(r.Context()).Name != "user" {
+			t.Errorf("Expected the route to be kept after setting the variables")
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}, "user")
+
+	var rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(mux.GET, "/api/users/42/", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected the handler to run, got %d", rec.Code)
+	}
+
+	want = "1"
+	var h, vars, _ = m.Resolve(mux.GET, "/api/users/1/")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(mux.GET, "/", nil))
+	h.ServeHTTP(rec, httptest.NewRequest(mux.GET, "/", nil))
+	if rec.Code != http.StatusAccepted || vars.Get("id") != "1" {
+		t.Fatalf("Expected the resolved variables to be kept, got %v", vars)
+	}
+
+	var ctx = mux.ContextWithRoute(context.Background(), api)
+	if mux.RouteFromContext(ctx) != api || mux.RouteFromContext(context.Background()) != nil {
+		t.Fatal("Expected the route to be stored in the context")
+	}
+	if mux.Vars(httptest.NewRequest(mux.GET, "/", nil)) != nil {
+		t.Fatal("Expected no variables outside of a match")
+	}
+}
+
+func TestMatchContextOutlivesRequest(t *testing.T) {
+	var m = mux.New()
+	var saved []context.Context
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		saved = append(saved, context.WithoutCancel(r.Context()))
+	}
+	m.HandleFunc(mux.GET, "/users/<<id>>/", handler, "user")
+	m.HandleFunc(mux.GET, "/posts/<<id>>/", handler, "post")
+
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(mux.GET, "/users/1/", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(mux.GET, "/posts/2/", nil))
+
+	for i, name := range []string{"user", "post"} {
+		if rt := mux.RouteFromContext(saved[i]); rt == nil || rt.Name != name {
+			t.Fatalf("Expected the context to keep the route %q after the request, got %v", name, rt)
+		}
+	}
+
+	// The variables belong to the request, they are not reused by the next one.
+	var kept []mux.Variables
+	m.HandleFunc(mux.GET, "/files/<<path...>>", func(w http.ResponseWriter, r *http.Request) {
+		kept = append(kept, mux.Vars(r))
+	})
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(mux.GET, "/files/a/b", nil))
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(mux.GET, "/files/c/d", nil))
+	if strings.Join(kept[0]["path"], "/") != "a/b" || strings.Join(kept[1]["path"], "/") != "c/d" {
+		t.Fatalf("Expected the variables to be kept after the request, got %v", kept)
+	}
+}
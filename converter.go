@@ -0,0 +1,191 @@
+package mux
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Converter validates the value of a path variable.
+//
+// Converters are referenced by name in a path, I.E. `<<id:int>>`.
+//
+// A segment which does not satisfy the converter of its variable
+// will not match, and matching will continue with the next route.
+type Converter interface {
+	Match(value string) bool
+}
+
+// ConverterFunc allows a plain function to be used as a Converter.
+type ConverterFunc func(value string) bool
+
+func (f ConverterFunc) Match(value string) bool {
+	return f(value)
+}
+
+// The name of the converter which allows a variable to capture
+// the remainder of the path, much like a GLOB.
+const PathConverter = "path"
+
+// The converters which are available to every Mux.
+var builtinConverters = map[string]Converter{
+	"int":         ConverterFunc(isInt),
+	"uint":        ConverterFunc(isUint),
+	"slug":        ConverterFunc(isSlug),
+	"uuid":        ConverterFunc(isUUID),
+	PathConverter: ConverterFunc(isNonEmpty),
+}
+
+// RegisterConverter registers a converter which can be used in paths of routes added to this Mux.
+//
+// Built-in converters (int, uint, slug, uuid, path) can be overridden.
+// Converters must be registered before the routes using them are added.
+func (r *Mux) RegisterConverter(name string, converter Converter) {
+	if name == "" || converter == nil {
+		panic("mux: RegisterConverter requires a name and a converter")
+	}
+	if r.converters == nil {
+		r.converters = make(map[string]Converter)
+	}
+	r.converters[name] = converter
+}
+
+// lookupConverter returns the converter registered under the name,
+// falling back to the built-in converters.
+func (r *Mux) lookupConverter(name string) (Converter, bool) {
+	if r != nil {
+		if c, ok := r.converters[name]; ok {
+			return c, true
+		}
+	}
+	c, ok := builtinConverters[name]
+	return c, ok
+}
+
+// bindConverters resolves the converters of the route and its children.
+//
+// It panics if a converter is referenced which was never registered.
+func (r *Mux) bindConverters(rt *Route) {
+	if rt.Path != nil {
+		r.bindParts(rt, rt.Path.Path)
+	}
+	if rt.Host != nil {
+		r.bindParts(rt, rt.Host.Parts)
+	}
+	for _, child := range rt.Children {
+		r.bindConverters(child)
+	}
+}
+
+// bindParts resolves the converters of the parts and their pieces.
+func (r *Mux) bindParts(rt *Route, parts []*PathPart) {
+	for _, part := range parts {
+		r.bindConverter(rt, part)
+		for _, piece := range part.Pieces {
+			r.bindConverter(rt, piece)
+		}
+	}
+}
+
+func (r *Mux) bindConverter(rt *Route, part *PathPart) {
+	if part.Converter == "" {
+		return
+	}
+	var c, ok = r.lookupConverter(part.Converter)
+	if !ok {
+		panic(fmt.Sprintf("mux: unknown converter %q in path %q", part.Converter, rt.Path.String()))
+	}
+	part.converter = c
+}
+
+// patternConverter validates a variable against an anchored regular expression.
+type patternConverter struct {
+	re *regexp.Regexp
+}
+
+func newPatternConverter(pattern string) Converter {
+	var re, err = regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		panic(fmt.Sprintf("mux: invalid pattern %q: %v", pattern, err))
+	}
+	return &patternConverter{re: re}
+}
+
+func (c *patternConverter) Match(value string) bool {
+	return c.re.MatchString(value)
+}
+
+// isConverterName reports whether the constraint of a variable refers to a converter
+// by name, instead of being a regular expression.
+func isConverterName(constraint string) bool {
+	if constraint == "" {
+		return false
+	}
+	for i := 0; i < len(constraint); i++ {
+		var c = constraint[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isInt(value string) bool {
+	if len(value) > 1 && value[0] == '-' {
+		value = value[1:]
+	}
+	return isUint(value)
+}
+
+func isUint(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isSlug(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		var c = value[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		var c = value[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isNonEmpty(value string) bool {
+	return value != ""
+}
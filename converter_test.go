@@ -0,0 +1,152 @@
+package mux_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestConverters(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+
+	m.RegisterConverter("upper", mux.ConverterFunc(func(value string) bool {
+		return value == strings.ToUpper(value)
+	}))
+
+	var (
+		byID    = m.Handle(mux.GET, "/users/<<id:int>>/", h, "by-id")
+		byUUID  = m.Handle(mux.GET, "/users/<<uid:uuid>>/", h, "by-uuid")
+		bySlug  = m.Handle(mux.GET, "/users/<<slug:slug>>/", h, "by-slug")
+		byUint  = m.Handle(mux.GET, "/pages/<<page:uint>>/", h, "by-page")
+		files   = m.Handle(mux.GET, "/files/<<file:path>>", h, "files")
+		byUpper = m.Handle(mux.GET, "/codes/<<code:upper>>/", h, "by-code")
+		byAny   = m.Handle(mux.GET, "/users/<<name>>/", h, "by-name")
+	)
+
+	var tests = []struct {
+		path     string
+		expected *mux.Route
+		vars     mux.Variables
+	}{
+		{"/users/42/", byID, mux.Variables{"id": {"42"}}},
+		{"/users/-42/", byID, mux.Variables{"id": {"-42"}}},
+		{"/users/123e4567-e89b-12d3-a456-426614174000/", byUUID, mux.Variables{"uid": {"123e4567-e89b-12d3-a456-426614174000"}}},
+		{"/users/john-doe/", bySlug, mux.Variables{"slug": {"john-doe"}}},
+		{"/users/john.doe/", byAny, mux.Variables{"name": {"john.doe"}}},
+		{"/pages/3/", byUint, mux.Variables{"page": {"3"}}},
+		{"/pages/-3/", nil, nil},
+		{"/files/a/b/c.txt", files, mux.Variables{"file": {"a", "b", "c.txt"}}},
+		{"/files/", nil, nil},
+		{"/codes/ABC/", byUpper, mux.Variables{"code": {"ABC"}}},
+		{"/codes/abc/", nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var route, vars = m.Match(mux.GET, test.path)
+			if route != test.expected {
+				t.Fatalf("Expected route %v, got %v", test.expected, route)
+			}
+			for k, v := range test.vars {
+				if strings.Join(vars[k], "/") != strings.Join(v, "/") {
+					t.Fatalf("Expected %s to be %v, got %v", k, v, vars[k])
+				}
+			}
+		})
+	}
+}
+
+func TestConverterUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected a panic for an unknown converter")
+		}
+	}()
+	mux.New().Handle(mux.GET, "/<<id:unknown>>/", mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {}))
+}
+
+func TestConverterReverse(t *testing.T) {
+	var info = mux.NewPathInfo(nil, "/users/<<id:int>>/<<rest:path>>")
+	if info.String() != "/users/<<id:int>>/<<rest:path>>" {
+		t.Fatalf("Expected path to round-trip, got %s", info.String())
+	}
+
+	var path, err = info.Reverse(42, "a/b")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if path != "/users/42/a/b" {
+		t.Fatalf("Expected /users/42/a/b, got %s", path)
+	}
+
+	if _, err = info.Reverse("john", "a/b"); !errors.Is(err, mux.ErrInvalidVariable) {
+		t.Fatalf("Expected %v, got %v", mux.ErrInvalidVariable, err)
+	}
+}
+
+func TestPatternConstraints(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+
+	var archive = m.Handle(mux.GET, "/archive/", h)
+	var (
+		byYear = archive.Handle(mux.GET, "/<<year:[0-9]{4}>>/", h)
+		byLang = archive.Handle(mux.GET, "/<<lang:en|nl|de>>/", h)
+		bySlug = archive.Handle(mux.GET, "/<<slug>>/", h)
+		exact  = m.Handle(mux.GET, "/exact/<<v:(en)>>/", h)
+	)
+
+	var tests = []struct {
+		path     string
+		expected *mux.Route
+		vars     mux.Variables
+	}{
+		{"/archive/2024/", byYear, mux.Variables{"year": {"2024"}}},
+		{"/archive/20245/", bySlug, mux.Variables{"slug": {"20245"}}},
+		{"/archive/nl/", byLang, mux.Variables{"lang": {"nl"}}},
+		{"/archive/fr/", bySlug, mux.Variables{"slug": {"fr"}}},
+		{"/archive/enn/", bySlug, mux.Variables{"slug": {"enn"}}},
+		{"/exact/en/", exact, mux.Variables{"v": {"en"}}},
+		{"/exact/nl/", nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var route, vars = m.Match(mux.GET, test.path)
+			if route != test.expected {
+				t.Fatalf("Expected route %v, got %v", test.expected, route)
+			}
+			for k, v := range test.vars {
+				if vars.Get(k) != v[0] {
+					t.Fatalf("Expected %s to be %v, got %v", k, v, vars[k])
+				}
+			}
+		})
+	}
+
+	if s := byYear.Path.String(); s != "/archive/<<year:[0-9]{4}>>" {
+		t.Fatalf("Expected pattern to round-trip, got %s", s)
+	}
+
+	var info = mux.NewPathInfo(nil, byLang.Path.String())
+	if info.String() != byLang.Path.String() {
+		t.Fatalf("Expected %s, got %s", byLang.Path.String(), info.String())
+	}
+
+	if _, err := byYear.Path.Reverse("abcd"); !errors.Is(err, mux.ErrInvalidVariable) {
+		t.Fatalf("Expected %v, got %v", mux.ErrInvalidVariable, err)
+	}
+}
+
+func TestPatternInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected a panic for an invalid pattern")
+		}
+	}()
+	mux.NewPathInfo(nil, "/<<id:[0-9>>/")
+}
@@ -10,4 +10,7 @@ const (
 	ErrRouteNotFound      = Error("route not found")
 	ErrTooManyVariables   = Error("too many variables provided to replace in path")
 	ErrNotEnoughVariables = Error("not enough variables provided to replace in path")
+	ErrInvalidVariable    = Error("variable does not satisfy the converter of the path")
+	ErrUnknownVariable    = Error("variable is not part of the path")
+	ErrMissingVariable    = Error("variable of the path was not provided")
 )
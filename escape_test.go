@@ -0,0 +1,88 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestEscapedVariables(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, mux.Vars(r))
+	})
+	m.Handle(mux.GET, "/files/<<name>>/", h, "file")
+	m.Handle(mux.GET, "/files/<<name>>/<<page:int>>/", h, "page")
+	m.Handle(mux.GET, "/static/*", h, "static")
+
+	var tests = []struct {
+		name      string
+		variables []interface{}
+		expected  string
+		vars      string
+	}{
+		{"file", []interface{}{"a/b"}, "/files/a%2Fb/", "map[name:[a/b]]"},
+		{"file", []interface{}{"what?#"}, "/files/what%3F%23/", "map[name:[what?#]]"},
+		{"file", []interface{}{"my report"}, "/files/my%20report/", "map[name:[my report]]"},
+		{"file", []interface{}{"100%"}, "/files/100%25/", "map[name:[100%]]"},
+		{"page", []interface{}{"a/b", 2}, "/files/a%2Fb/2/", "map[name:[a/b] page:[2]]"},
+		{"static", []interface{}{"css dir", "app.css"}, "/static/css%20dir/app.css", "map[*:[css dir app.css]]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var path, err = m.Reverse(test.name, test.variables...)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if path != test.expected {
+				t.Fatalf("Expected %q, got %q", test.expected, path)
+			}
+
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(mux.GET, path, nil))
+			if w.Body.String() != test.vars {
+				t.Fatalf("Expected %q, got %q", test.vars, w.Body.String())
+			}
+		})
+	}
+
+	var u, err = m.ReverseURL("file", "a/b")
+	if err != nil || u.String() != "/files/a%2Fb/" {
+		t.Fatalf("Expected /files/a%%2Fb/, got %v (%v)", u, err)
+	}
+}
+
+func FuzzReverseMatch(f *testing.F) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	m.Handle(mux.GET, "/users/<<user>>/posts/<<title>>/", h, "post")
+
+	f.Add("alice", "hello world")
+	f.Add("a/b", "?#")
+	f.Add("%2F", "..")
+	f.Add("ünïcode", "100%")
+
+	f.Fuzz(func(t *testing.T, user, title string) {
+		// Empty variables cannot be matched.
+		if user == "" || title == "" {
+			t.Skip()
+		}
+
+		var path, err = m.Reverse("post", user, title)
+		if err != nil {
+			t.Fatalf("Reverse(%q, %q): %v", user, title, err)
+		}
+
+		var route, vars = m.Match(mux.GET, path)
+		if route == nil {
+			t.Fatalf("Match(%q): no route", path)
+		}
+		if vars.Get("user") != user || vars.Get("title") != title {
+			t.Fatalf("Match(%q): expected %q and %q, got %v", path, user, title, vars)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Rejection is the reason a route did not match, see Mux.Explain.
+type Rejection string
+
+const (
+	RejectedPath     Rejection = "path"     // A segment did not match the pattern of the route.
+	RejectedPartial  Rejection = "partial"  // The pattern matched, but segments were left for the children of the route.
+	RejectedMethod   Rejection = "method"   // The route is registered for another method.
+	RejectedHandler  Rejection = "handler"  // The route has no handler, I.E. it only groups its children.
+	RejectedSlash    Rejection = "slash"    // The trailing slash differs, with TrailingSlashStrict.
+	RejectedHost     Rejection = "host"     // The host did not match the host pattern of the route.
+	RejectedMatcher  Rejection = "matcher"  // A matcher of the route or one of its parents failed.
+	RejectedShadowed Rejection = "shadowed" // The route matched, but a more specific route was picked.
+)
+
+// Attempt is a route which was tried while explaining a match.
+type Attempt struct {
+	Route *Route `json:"-"`
+	Name  string `json:"name,omitempty"`
+
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+	Depth   int    `json:"depth"`
+
+	// The index of the segment the route started matching at,
+	// and the index returned by PathInfo.Match; -1 if the pattern did not match.
+	From     int `json:"from"`
+	NextFrom int `json:"next_from"`
+
+	// The index of the segment which did not match the pattern, or -1.
+	// It equals the number of segments if the path was too short.
+	Segment      int    `json:"segment"`
+	SegmentValue string `json:"segment_value,omitempty"`
+
+	// Why the route was rejected, empty for the route which matched.
+	Rejected Rejection `json:"rejected,omitempty"`
+}
+
+// Matched reports whether the attempt is the route which was picked.
+func (a Attempt) Matched() bool {
+	return a.Rejected == ""
+}
+
+// Explanation describes how a path was matched, see Mux.Explain.
+type Explanation struct {
+	Method   string   `json:"method"`
+	Host     string   `json:"host,omitempty"`
+	Path     string   `json:"path"`
+	Segments []string `json:"segments"`
+
+	// The route which was picked and its variables, nil if no route matched.
+	Route     *Route    `json:"-"`
+	Variables Variables `json:"variables,omitempty"`
+
+	// All routes which were tried, in the order of Walk.
+	// The children of a route are only tried if its pattern matched.
+	Attempts []Attempt `json:"attempts"`
+}
+
+// Explain matches the method and path like Match, and reports every route it tried
+// and why it was rejected. It is meant for debugging, I.E. to find out why a request was not found.
+//
+// Routes are tried one by one with PathInfo.Match. The route which was picked
+// is always the one returned by Match, the attempts do not account for case-insensitive matching.
+func (r *Mux) Explain(method, path string) *Explanation {
+	return r.explain(method, "", path, nil)
+}
+
+// ExplainHost is like Explain, but also checks the host patterns of routes.
+func (r *Mux) ExplainHost(method, host, path string) *Explanation {
+	return r.explain(method, host, path, nil)
+}
+
+// ExplainRequest is like Explain, but also checks the host patterns and matchers of routes.
+func (r *Mux) ExplainRequest(req *http.Request) *Explanation {
+	return r.explain(req.Method, GetHost(req), req.URL.EscapedPath(), req)
+}
+
+func (r *Mux) explain(method, host, path string, req *http.Request) *Explanation {
+	var st = getMatchState(false)
+	defer st.release()
+
+	var in = r.newMatchInput(st, method, host, path, req)
+	var e = &Explanation{
+		Method:   in.method,
+		Host:     in.host,
+		Path:     path,
+		Segments: slices.Clone(in.path),
+		Attempts: make([]Attempt, 0),
+	}
+	e.Route, e.Variables, _ = r.compiled().match(st, in)
+
+	var try func(rt *Route, from, depth int, vars Variables)
+	try = func(rt *Route, from, depth int, vars Variables) {
+		var ok, next, v = rt.Path.Match(in.path, from, maps.Clone(vars))
+		var a = Attempt{
+			Route:    rt,
+			Name:     rt.FullName(),
+			Method:   rt.Method,
+			Pattern:  rt.Path.String(),
+			Depth:    depth,
+			From:     from,
+			NextFrom: next,
+			Segment:  -1,
+		}
+
+		switch {
+		case next == -1:
+			a.Rejected = RejectedPath
+			a.Segment = failedSegment(rt.Path, in.path, from)
+			if a.Segment < len(in.path) {
+				a.SegmentValue = in.path[a.Segment]
+			}
+		case !ok:
+			a.Rejected = RejectedPartial
+		default:
+			a.Rejected = explainRoute(rt, in, e.Route)
+		}
+
+		e.Attempts = append(e.Attempts, a)
+		if next == -1 {
+			return
+		}
+
+		for _, child := range rt.Children {
+			try(child, next, depth+1, v)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rt := range r.routes {
+		try(rt, 0, 0, nil)
+	}
+	return e
+}
+
+// explainRoute returns why a route which matched the full path was rejected, or "" if it was picked.
+func explainRoute(rt *Route, in matchInput, picked *Route) Rejection {
+	switch {
+	case rt.Method != ANY && rt.Method != in.method && in.method != ANY:
+		return RejectedMethod
+	case rt.Handler == nil:
+		return RejectedHandler
+	case in.strictSlash && !rt.Path.hasGlob() && len(in.path) > 0 && rt.Path.trailingSlash() != in.slash:
+		return RejectedSlash
+	}
+
+	if host := rt.HostPattern(); host != nil && in.host != "" {
+		if _, ok := host.Match(in.host); !ok {
+			return RejectedHost
+		}
+	}
+
+	if in.req != nil && !rt.matches(in.req) {
+		return RejectedMatcher
+	}
+
+	if rt != picked {
+		return RejectedShadowed
+	}
+	return ""
+}
+
+// failedSegment returns the index of the first segment from which does not match the parts of the path.
+func failedSegment(p *PathInfo, path []string, from int) int {
+	var i = from
+	for k, part := range p.Path {
+		if i >= len(path) {
+			return i
+		}
+
+		switch {
+		case part.IsGlob && k < len(p.Path)-1:
+			// Backtracking globs are not explained any further.
+			return i
+		case part.IsGlob:
+			if p.Resolver != nil || !part.Validate(strings.Join(path[i:], URL_DELIM)) {
+				return i
+			}
+			return -1
+		case part.IsVariable:
+			if _, ok := part.capture(path[i], nil, false); !ok {
+				return i
+			}
+		case part.Part != path[i]:
+			return i
+		}
+		i++
+	}
+	return -1
+}
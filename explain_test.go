@@ -0,0 +1,85 @@
+package mux_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestExplain(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+
+	var api = m.Handle(mux.ANY, "/api/", nil, "api")
+	api.Handle(mux.POST, "/users/<<id:int>>/", h, "create")
+	api.Handle(mux.GET, "/users/<<id:int>>/", h, "user").Headers("X-Version", "2")
+	api.Handle(mux.GET, "/users/<<name>>/", h, "by-name")
+	api.Handle(mux.GET, "/users/me/", h, "me")
+	m.Handle(mux.GET, "/about/", h, "about")
+
+	var tests = []struct {
+		method   string
+		path     string
+		header   string
+		picked   string
+		attempts map[string]mux.Attempt
+	}{
+		{mux.GET, "/api/users/me/", "", "api:me", map[string]mux.Attempt{
+			"api":         {From: 0, NextFrom: 1, Segment: -1, Rejected: mux.RejectedPartial},
+			"api:create":  {From: 1, NextFrom: -1, Segment: 2, SegmentValue: "me", Rejected: mux.RejectedPath},
+			"api:by-name": {From: 1, NextFrom: 3, Segment: -1, Rejected: mux.RejectedShadowed},
+			"api:me":      {From: 1, NextFrom: 3, Segment: -1},
+			"about":       {From: 0, NextFrom: -1, Segment: 0, SegmentValue: "api", Rejected: mux.RejectedPath},
+		}},
+		{mux.GET, "/api/users/1/", "", "api:by-name", map[string]mux.Attempt{
+			"api:create": {From: 1, NextFrom: 3, Segment: -1, Rejected: mux.RejectedMethod},
+			"api:user":   {From: 1, NextFrom: 3, Segment: -1, Rejected: mux.RejectedMatcher},
+		}},
+		{mux.GET, "/api/users/1/", "2", "api:user", map[string]mux.Attempt{
+			"api:user":    {From: 1, NextFrom: 3, Segment: -1},
+			"api:by-name": {From: 1, NextFrom: 3, Segment: -1, Rejected: mux.RejectedShadowed},
+		}},
+		{mux.GET, "/api/", "", "", map[string]mux.Attempt{
+			"api":    {From: 0, NextFrom: 1, Segment: -1, Rejected: mux.RejectedHandler},
+			"api:me": {From: 1, NextFrom: -1, Segment: 1, Rejected: mux.RejectedPath},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var req = httptest.NewRequest(test.method, test.path, nil)
+			if test.header != "" {
+				req.Header.Set("X-Version", test.header)
+			}
+
+			var e = m.ExplainRequest(req)
+			if e.Route != nil && e.Route.FullName() != test.picked || e.Route == nil && test.picked != "" {
+				t.Fatalf("Expected %q to be picked, got %v", test.picked, e.Route)
+			}
+			if len(e.Attempts) != 6 {
+				t.Fatalf("Expected 6 attempts, got %d", len(e.Attempts))
+			}
+
+			for _, a := range e.Attempts {
+				var expected, ok = test.attempts[a.Name]
+				if !ok {
+					continue
+				}
+				if a.From != expected.From || a.NextFrom != expected.NextFrom || a.Segment != expected.Segment ||
+					a.SegmentValue != expected.SegmentValue || a.Rejected != expected.Rejected {
+					t.Fatalf("Expected %s to be %+v, got %+v", a.Name, expected, a)
+				}
+				if a.Matched() != (a.Name == test.picked) {
+					t.Fatalf("Expected only %q to be matched, got %s", test.picked, a.Name)
+				}
+			}
+		})
+	}
+
+	// Without a request matchers are not checked.
+	if e := m.Explain(mux.GET, "/api/users/1/"); e.Route == nil || e.Route.Name != "user" {
+		t.Fatalf("Expected api:user to be picked, got %v", e.Route)
+	}
+}
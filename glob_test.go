@@ -0,0 +1,106 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestNamedAndMiddleGlobs(t *testing.T) {
+	var m = mux.New()
+	var write = func(name string) mux.Handler {
+		return mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %v", name, mux.Vars(r))
+		})
+	}
+	m.Handle(mux.GET, "/files/<<path...>>", write("files"), "files")
+	m.Handle(mux.GET, "/files/private/<<private...>>", write("private"), "private")
+	m.Handle(mux.GET, "/repos/<<repo...>>/blob/<<ref>>/", write("blob"), "blob")
+	m.Handle(mux.GET, "/repos/<<repo...>>/tree/", write("tree"), "tree")
+	m.Handle(mux.GET, "/docs/*/edit/", write("edit"), "edit")
+
+	var tests = []struct {
+		path     string
+		expected string
+	}{
+		{"/files/a/b.txt", "files map[path:[a b.txt]]"},
+		{"/files/private/a/b.txt", "private map[private:[a b.txt]]"},
+		{"/repos/org/project/blob/main/", "blob map[ref:[main] repo:[org project]]"},
+		{"/repos/org/blob/blob/main/", "blob map[ref:[main] repo:[org blob]]"},
+		{"/repos/org/team/project/tree/", "tree map[repo:[org team project]]"},
+		{"/docs/guide/intro/edit/", "edit map[*:[guide intro]]"},
+		{"/repos/blob/main/", ""},
+		{"/docs/edit/", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(mux.GET, test.path, nil))
+			if test.expected == "" {
+				if w.Code != http.StatusNotFound {
+					t.Fatalf("Expected a 404, got %d %q", w.Code, w.Body.String())
+				}
+				return
+			}
+			if w.Body.String() != test.expected {
+				t.Fatalf("Expected %q, got %q", test.expected, w.Body.String())
+			}
+		})
+	}
+
+	var reverse = []struct {
+		name      string
+		variables []interface{}
+		expected  string
+	}{
+		{"files", []interface{}{[]string{"a", "b c.txt"}}, "/files/a/b%20c.txt"},
+		{"files", []interface{}{"a", "b.txt"}, "/files/a/b.txt"},
+		{"blob", []interface{}{[]string{"org", "project"}, "main"}, "/repos/org/project/blob/main/"},
+		{"blob", []interface{}{"org/project", "main"}, "/repos/org/project/blob/main/"},
+		{"edit", []interface{}{[]string{"guide", "intro"}}, "/docs/guide/intro/edit/"},
+	}
+
+	for _, test := range reverse {
+		var path, err = m.Reverse(test.name, test.variables...)
+		if err != nil || path != test.expected {
+			t.Errorf("Reverse(%q, %v): expected %q, got %q (%v)", test.name, test.variables, test.expected, path, err)
+		}
+	}
+
+	if _, err := m.Reverse("blob", []string{}, "main"); err != mux.ErrInvalidVariable {
+		t.Errorf("Expected %v for an empty glob followed by other parts, got %v", mux.ErrInvalidVariable, err)
+	}
+}
+
+func TestPathInfoMatchMiddleGlob(t *testing.T) {
+	var info = mux.NewPathInfo(nil, "/repos/<<repo...>>/<<kind:blob|tree>>/<<ref>>/")
+	if info.String() != "/repos/<<repo...>>/<<kind:blob|tree>>/<<ref>>" {
+		t.Fatalf("Expected the path to round-trip, got %q", info.String())
+	}
+
+	var ok, _, vars = info.Match(mux.SplitPath("/repos/a/b/tree/c/blob/main/"), 0, nil)
+	if !ok {
+		t.Fatal("Expected the path to match")
+	}
+	if !slices.Equal(vars["repo"], []string{"a", "b", "tree", "c"}) || vars.Get("kind") != "blob" || vars.Get("ref") != "main" {
+		t.Fatalf("Expected the glob to capture up to the last parts, got %v", vars)
+	}
+
+	if ok, _, _ = info.Match(mux.SplitPath("/repos/blob/main/"), 0, nil); ok {
+		t.Fatal("Expected a glob followed by other parts to require a segment")
+	}
+}
+
+func TestMultipleGlobsPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected a path with two globs to panic")
+		}
+	}()
+	mux.NewPathInfo(nil, "/<<a...>>/x/*")
+}
@@ -0,0 +1,172 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var _ Multiplexer = (*routeGroup)(nil)
+
+// routeGroup adds routes to a mux or route with a shared prefix, name and middleware.
+//
+// It does not add a route of its own, the routes are added to the parent directly.
+type routeGroup struct {
+	parent     Multiplexer
+	mux        *Mux
+	prefix     string
+	name       string
+	middleware []Middleware
+}
+
+// Group calls fn with a Multiplexer which adds routes to the mux below the prefix.
+//
+// The name, if given, comes before the names of all routes added to the group in their PathName and FullName,
+// their own names are not changed, see Route.NamePrefix.
+// Middleware added with Use in fn runs for the routes which are added to the group after it,
+// after the middleware of the routes themselves and the middleware they inherit from their parent.
+//
+// Unlike a route without a handler, the group does not take part in matching,
+// the routes are added to the mux as if they were registered with the full prefix.
+//
+//	m.Group("/api/", func(g mux.Multiplexer) {
+//		g.Use(authenticate)
+//		g.Get("/users/", users, "users") // GET /api/users/, named api:users
+//	}, "api")
+func (r *Mux) Group(prefix string, fn func(g Multiplexer), name ...string) {
+	fn(newGroup(r, r, prefix, name))
+}
+
+// Group calls fn with a Multiplexer which adds children to the route below the prefix, see Mux.Group.
+func (r *Route) Group(prefix string, fn func(g Multiplexer), name ...string) {
+	fn(newGroup(r, r.ParentMux, prefix, name))
+}
+
+func newGroup(parent Multiplexer, m *Mux, prefix string, name []string) *routeGroup {
+	var g = &routeGroup{
+		parent: parent,
+		mux:    m,
+		prefix: strings.Trim(prefix, URL_DELIM),
+	}
+	if len(name) > 0 {
+		g.name = name[0]
+	}
+	return g
+}
+
+func (g *routeGroup) Use(middleware ...Middleware) {
+	g.middleware = append(g.middleware, middleware...)
+}
+
+// Handle adds the route to the parent of the group, with the prefix, name and middleware of the group.
+func (g *routeGroup) Handle(method string, path string, handler Handler, name ...string) *Route {
+	var route = newRoute(method, handler, name...)
+	route.ParentMux = g.mux
+	route.Path = NewPathInfo(route, g.path(path))
+	g.add(route)
+	return route
+}
+
+func (g *routeGroup) HandleFunc(method string, path string, handler func(w http.ResponseWriter, r *http.Request), name ...string) *Route {
+	return g.Handle(method, path, NewHandler(handler), name...)
+}
+
+// AddRoute adds the route to the parent of the group, with the prefix, name and middleware of the group.
+//
+// The parts of the prefix are put before the parts of the path of the route.
+func (g *routeGroup) AddRoute(route *Route) {
+	if g.prefix != "" {
+		route.ParentMux = g.mux
+		route.Path = g.prefixed(route)
+		rebase(route)
+	}
+	g.add(route)
+}
+
+// add prefixes the name of the route and adds it to the parent of the group.
+//
+// The middleware of the group is added while the routes of the parent are still locked,
+// so the route is never served without it.
+func (g *routeGroup) add(route *Route) {
+	if g.name != "" {
+		route.NamePrefix = append([]string{g.name}, route.NamePrefix...)
+	}
+
+	switch parent := g.parent.(type) {
+	case *Mux:
+		parent.mu.Lock()
+		defer parent.mu.Unlock()
+		parent.addRoute(route)
+	case *Route:
+		defer parent.lock()()
+		parent.addRoute(route)
+	}
+	g.use(route)
+}
+
+// use adds the middleware of the group to the route and its children,
+// after the middleware they inherited from their parents.
+func (g *routeGroup) use(route *Route) {
+	if len(g.middleware) > 0 {
+		route.Middleware = append(slices.Clone(route.Middleware), g.middleware...)
+	}
+	for _, child := range route.Children {
+		g.use(child)
+	}
+}
+
+// path returns the path below the prefix of the group.
+func (g *routeGroup) path(path string) string {
+	if g.prefix == "" {
+		return path
+	}
+	return URL_DELIM + g.prefix + URL_DELIM + strings.TrimPrefix(path, URL_DELIM)
+}
+
+// prefixed returns a copy of the path of the route with the parts of the prefix before its own parts.
+//
+// The path keeps its trailing slash, the root of the group ends in a slash like it does in Handle.
+func (g *routeGroup) prefixed(route *Route) *PathInfo {
+	var prefix = NewPathInfo(route, URL_DELIM+g.prefix)
+	var p = *route.Path
+	p.Path = append(prefix.Path, route.Path.Path...)
+	if len(route.Path.Path) == 0 {
+		p.TrailingSlash = true
+	}
+	return &p
+}
+
+// rebase points the paths of the children of the route to its current path.
+func rebase(route *Route) {
+	for _, child := range route.Children {
+		child.Path = child.Path.WithParent(route.Path)
+		rebase(child)
+	}
+}
+
+func (g *routeGroup) Any(path string, handler Handler, name ...string) *Route {
+	return g.Handle(ANY, path, handler, name...)
+}
+
+func (g *routeGroup) Get(path string, handler Handler, name ...string) *Route {
+	return g.Handle(GET, path, handler, name...)
+}
+
+func (g *routeGroup) Post(path string, handler Handler, name ...string) *Route {
+	return g.Handle(POST, path, handler, name...)
+}
+
+func (g *routeGroup) Put(path string, handler Handler, name ...string) *Route {
+	return g.Handle(PUT, path, handler, name...)
+}
+
+func (g *routeGroup) Patch(path string, handler Handler, name ...string) *Route {
+	return g.Handle(PATCH, path, handler, name...)
+}
+
+func (g *routeGroup) Delete(path string, handler Handler, name ...string) *Route {
+	return g.Handle(DELETE, path, handler, name...)
+}
@@ -0,0 +1,158 @@
+package mux_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func groupMiddleware(tag string) mux.Middleware {
+	return func(next mux.Handler) mux.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, tag+" ")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func groupHandler(w http.ResponseWriter, r *http.Request) {
+	io.WriteString(w, mux.RoutePattern(r))
+}
+
+func TestGroup(t *testing.T) {
+	var m = mux.New()
+	m.HandleFunc(mux.GET, "/about/", groupHandler, "about")
+
+	m.Group("/api/", func(g mux.Multiplexer) {
+		g.Use(groupMiddleware("auth"))
+		var users = g.HandleFunc(mux.GET, "/users/", groupHandler, "users")
+		users.Use(groupMiddleware("users"))
+		users.HandleFunc(mux.GET, "/<<id:int>>/", groupHandler, "detail")
+
+		g.AddRoute(mux.NewRoute(mux.POST, "/posts/", mux.NewHandler(groupHandler), "posts"))
+	}, "api")
+
+	var tests = map[string]string{
+		"/about/":       "/about",
+		"/api/users/":   "auth users /api/users",
+		"/api/users/1/": "auth users /api/users/<<id:int>>",
+	}
+	for path, expected := range tests {
+		var w = httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(mux.GET, path, nil))
+		if w.Body.String() != expected {
+			t.Fatalf("%s: expected %q, got %q", path, expected, w.Body.String())
+		}
+	}
+
+	var w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.POST, "/api/posts/", nil))
+	if w.Body.String() != "auth /api/posts" {
+		t.Fatalf("Expected the added route to run the group middleware, got %q", w.Body.String())
+	}
+
+	if path, err := m.Reverse("api:users:detail", 1); err != nil || path != "/api/users/1/" {
+		t.Fatalf("Expected /api/users/1/, got %q %v", path, err)
+	}
+
+	// The name of the group is kept apart from the names of the routes.
+	var detail = m.Find("api:users:detail")
+	if detail.Name != "detail" || strings.Join(detail.PathName(), " ") != "api users detail" {
+		t.Fatalf("Expected the group to prefix the path name, got %q %v", detail.Name, detail.PathName())
+	}
+	var users = detail.Parent
+	if rt, ok := users.Find(strings.Split("api:users:detail", mux.NAME_SEPARATOR)); !ok || rt != detail {
+		t.Fatalf("Expected Route.Find to find the grouped route, got %v", rt)
+	}
+
+	var names []string
+	m.Walk(func(rt *mux.Route, depth int) error {
+		names = append(names, strings.Repeat("-", depth)+rt.FullName())
+		return nil
+	})
+	if strings.Join(names, " ") != "about api:users -api:users:detail api:posts" {
+		t.Fatalf("Expected the group not to add a route, got %v", names)
+	}
+
+	// The group does not take part in matching.
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.GET, "/api/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected the prefix of the group to be not found, got %d", w.Code)
+	}
+}
+
+func TestRouteGroup(t *testing.T) {
+	var m = mux.New()
+	var api = m.Handle(mux.ANY, "/api/", nil, "api")
+	api.Group("/v1/", func(g mux.Multiplexer) {
+		g.Use(groupMiddleware("v1"))
+		g.HandleFunc(mux.GET, "/items/<<id>>/", groupHandler, "item")
+	}, "v1")
+
+	if len(api.Children) != 1 {
+		t.Fatalf("Expected the route to be added to the parent directly, got %d children", len(api.Children))
+	}
+
+	var w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.GET, "/api/v1/items/a/", nil))
+	if w.Body.String() != "v1 /api/v1/items/<<id>>" {
+		t.Fatalf("Unexpected response %q", w.Body.String())
+	}
+
+	if path, err := m.Reverse("api:v1:item", "a"); err != nil || path != "/api/v1/items/a/" {
+		t.Fatalf("Expected /api/v1/items/a/, got %q %v", path, err)
+	}
+}
+
+func TestGroupAddRouteTrailingSlash(t *testing.T) {
+	var m = mux.New()
+	m.TrailingSlash = mux.TrailingSlashStrict
+	m.Group("/g/", func(g mux.Multiplexer) {
+		g.AddRoute(mux.NewRoute(mux.GET, "/a/", mux.NewHandler(groupHandler)))
+		g.AddRoute(mux.NewRoute(mux.GET, "/b", mux.NewHandler(groupHandler)))
+	})
+
+	var tests = map[string]int{
+		"/g/a/": http.StatusOK,
+		"/g/a":  http.StatusNotFound,
+		"/g/b":  http.StatusOK,
+		"/g/b/": http.StatusNotFound,
+	}
+	for path, status := range tests {
+		var w = httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(mux.GET, path, nil))
+		if w.Code != status {
+			t.Fatalf("%s: expected status %d, got %d", path, status, w.Code)
+		}
+	}
+}
+
+func TestGroupNestedOrder(t *testing.T) {
+	var m = mux.New()
+	var api = m.Handle(mux.ANY, "/api/", nil)
+	api.Use(groupMiddleware("parent"))
+	api.Group("/v1/", func(g mux.Multiplexer) {
+		g.Use(groupMiddleware("group"))
+		var users = g.Handle(mux.ANY, "/users/", nil)
+		users.HandleFunc(mux.GET, "/<<id>>/", groupHandler, "detail")
+	}, "v1")
+
+	var w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.GET, "/api/v1/users/1/", nil))
+	if w.Body.String() != "parent group /api/v1/users/<<id>>" {
+		t.Fatalf("Expected the group middleware to run after the inherited middleware, got %q", w.Body.String())
+	}
+
+	var route = m.Find(":v1::detail")
+	if route == nil {
+		t.Fatal("Expected the name of the group to apply to unnamed routes")
+	}
+	if path, err := m.Reverse(route.FullName(), 1); err != nil || path != "/api/v1/users/1/" {
+		t.Fatalf("Expected /api/v1/users/1/, got %q %v", path, err)
+	}
+}
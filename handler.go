@@ -7,6 +7,10 @@ import "net/http"
 
 type Handler = http.Handler
 
+// BindableHandler is bound to each request it serves, Bind returns the handler which serves it.
+//
+// The handler is bound inside the middleware chain of the route, Bind sees the request
+// as changed by the middleware and runs after the middleware ran.
 type BindableHandler interface {
 	Handler
 	Bind(r *http.Request, rt *Route, vars Variables) Handler
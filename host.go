@@ -0,0 +1,145 @@
+package mux
+
+import (
+	"strings"
+)
+
+// The delimiter between the labels of a host.
+var HOST_DELIM = "."
+
+// HostInfo contains information about a host pattern.
+//
+// A host pattern is split into labels, each label can be static,
+// a variable, or a mix of both, I.E. `<<tenant>>.example.com` or `api.example.com`.
+type HostInfo struct {
+	Parts []*PathPart
+}
+
+// NewHostInfo creates a new HostInfo object from a host pattern.
+//
+// Static labels are matched case-insensitively.
+func NewHostInfo(rt *Route, host string) *HostInfo {
+	var m *Mux
+	if rt != nil {
+		m = rt.ParentMux
+	}
+
+	var labels = strings.Split(strings.Trim(host, HOST_DELIM), HOST_DELIM)
+	var info = &HostInfo{
+		Parts: make([]*PathPart, 0, len(labels)),
+	}
+	for _, label := range labels {
+		var part = parseSegment(m, label)
+		if !part.IsVariable {
+			part.Part = strings.ToLower(part.Part)
+		}
+		for _, piece := range part.Pieces {
+			if !piece.IsVariable {
+				piece.Part = strings.ToLower(piece.Part)
+			}
+		}
+		info.Parts = append(info.Parts, part)
+	}
+	return info
+}
+
+// String returns the host pattern.
+func (h *HostInfo) String() string {
+	var labels = make([]string, len(h.Parts))
+	for i, part := range h.Parts {
+		labels[i] = part.String()
+	}
+	return strings.Join(labels, HOST_DELIM)
+}
+
+// Match matches a host (without port) to this host pattern.
+//
+// It returns the variables captured from the host, and whether the host matched.
+func (h *HostInfo) Match(host string) (Variables, bool) {
+	var labels = strings.Split(strings.ToLower(host), HOST_DELIM)
+	if len(labels) != len(h.Parts) {
+		return nil, false
+	}
+
+	var vars Variables
+	var captures = make([]string, 0, len(h.Parts))
+	for i, part := range h.Parts {
+		var ok bool
+		var from = len(captures)
+		if captures, ok = part.capture(labels[i], captures, false); !ok {
+			return nil, false
+		}
+		for j, name := range part.Names() {
+			if vars == nil {
+				vars = make(Variables)
+			}
+			vars[name] = append(vars[name], captures[from+j])
+		}
+	}
+	return vars, true
+}
+
+// Reverse returns the host with the variables replaced.
+//
+// It returns the number of variables which were used.
+func (h *HostInfo) Reverse(variables ...interface{}) (string, int, error) {
+	var (
+		b     strings.Builder
+		index int
+		err   error
+	)
+	for i, part := range h.Parts {
+		if i > 0 {
+			b.WriteString(HOST_DELIM)
+		}
+		if index, err = part.reverse(&b, variables, index); err != nil {
+			return "", index, err
+		}
+	}
+	return b.String(), index, nil
+}
+
+// signature identifies host patterns which match the same hosts.
+func (h *HostInfo) signature() string {
+	if h == nil {
+		return ""
+	}
+	var b strings.Builder
+	for i, part := range h.Parts {
+		if i > 0 {
+			b.WriteString(HOST_DELIM)
+		}
+		b.WriteString(part.signature())
+	}
+	return b.String()
+}
+
+// WithHost restricts the route and its children to requests for hosts matching the pattern.
+//
+// Variables captured from the host are stored in the Variables next to those of the path.
+func (r *Route) WithHost(pattern string) *Route {
+	var host = NewHostInfo(r, pattern)
+	defer r.lock()()
+	if r.ParentMux != nil {
+		// Only the parts of the new host are bound, the parts of the
+		// path may be read by requests which are being matched.
+		r.ParentMux.bindParts(r, host.Parts)
+	}
+	r.Host = host
+	if r.ParentMux != nil {
+		r.ParentMux.routeChanged(r)
+		r.ParentMux.invalidate()
+	}
+	return r
+}
+
+// HostPattern returns the host pattern which applies to the route,
+// which is the pattern of the closest route (or parent) with a host set.
+func (r *Route) HostPattern() *HostInfo {
+	for curr := r; curr != nil; curr = curr.Parent {
+		if curr.Host != nil {
+			return curr.Host
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestHostRouting(t *testing.T) {
+	var m = mux.New()
+	var write = func(name string) mux.Handler {
+		return mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %v", name, mux.Vars(r))
+		})
+	}
+
+	var api = m.Host("api.example.com")
+	api.Handle(mux.GET, "/users/<<id:int>>/", write("api"), "users")
+
+	var tenant = m.Host("<<tenant:slug>>.example.com")
+	tenant.Handle(mux.GET, "/users/<<id:int>>/", write("tenant"), "users")
+
+	m.Handle(mux.GET, "/users/<<id:int>>/", write("default"), "users")
+
+	var tests = []struct {
+		host     string
+		path     string
+		expected string
+	}{
+		{"api.example.com", "/users/1/", "api map[id:[1]]"},
+		{"API.example.com:8080", "/users/1/", "api map[id:[1]]"},
+		{"acme.example.com", "/users/2/", "tenant map[id:[2] tenant:[acme]]"},
+		{"example.com", "/users/3/", "default map[id:[3]]"},
+		{"a.b.example.com", "/users/4/", "default map[id:[4]]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			var req = httptest.NewRequest(mux.GET, test.path, nil)
+			req.Host = test.host
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, req)
+			if w.Body.String() != test.expected {
+				t.Fatalf("Expected %q, got %q", test.expected, w.Body.String())
+			}
+		})
+	}
+
+	var route, vars = m.MatchHost(mux.GET, "acme.example.com", "/users/2/")
+	if route == nil || route.HostPattern() != tenant.Host || vars.Get("tenant") != "acme" {
+		t.Fatalf("Expected the tenant route with variables, got %v %v", route, vars)
+	}
+
+	// Without a host, the host patterns are not checked and the route without one is preferred.
+	if route, _ = m.Match(mux.GET, "/users/2/"); route == nil || route.HostPattern() != nil {
+		t.Fatalf("Expected the route without a host pattern, got %v", route)
+	}
+
+	if tenant.Host.String() != "<<tenant:slug>>.example.com" {
+		t.Fatalf("Expected host pattern to round-trip, got %s", tenant.Host.String())
+	}
+}
+
+func TestHostReverseURL(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	m.Host("<<tenant>>.example.com", "tenant").Handle(mux.GET, "/users/<<id:int>>/", h, "users")
+	m.Handle(mux.GET, "/about/", h, "about")
+
+	var u, err = m.ReverseURL("tenant:users", "acme", 42)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if u.String() != "//acme.example.com/users/42/" {
+		t.Fatalf("Expected //acme.example.com/users/42/, got %s", u.String())
+	}
+
+	u, err = m.ReverseURL("about")
+	if err != nil || u.String() != "/about/" {
+		t.Fatalf("Expected /about/, got %v (%v)", u, err)
+	}
+
+	if _, err = m.ReverseURL("tenant:users", "acme"); err != mux.ErrNotEnoughVariables {
+		t.Fatalf("Expected %v, got %v", mux.ErrNotEnoughVariables, err)
+	}
+}
@@ -6,14 +6,71 @@ import (
 )
 
 func GetHost(r *http.Request) string {
-	var host string
-	host = r.Host
-	if strings.Contains(host, ":") {
-		host = strings.Split(host, ":")[0]
-	}
+	var host, _, _ = strings.Cut(r.Host, ":")
 	return host
 }
 
+// GetScheme returns the scheme of the request, in lowercase.
+func GetScheme(r *http.Request) string {
+	if r.URL != nil && r.URL.Scheme != "" {
+		return strings.ToLower(r.URL.Scheme)
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// GetForwardedScheme returns the scheme the client used to make the request, in lowercase.
+//
+// If proxied is true, the Forwarded and X-Forwarded-Proto headers are taken into account.
+// Only set proxied if the request passed through a proxy which sets or strips these headers.
+func GetForwardedScheme(r *http.Request, proxied bool) string {
+	if proxied {
+		if proto := forwardedParam(r, "proto"); proto != "" {
+			return strings.ToLower(proto)
+		}
+		if proto := firstHeaderValue(r, "X-Forwarded-Proto"); proto != "" {
+			return strings.ToLower(proto)
+		}
+	}
+	return GetScheme(r)
+}
+
+// GetForwardedHost returns the host the client made the request to, including the port.
+//
+// If proxied is true, the Forwarded and X-Forwarded-Host headers are taken into account.
+// Only set proxied if the request passed through a proxy which sets or strips these headers.
+func GetForwardedHost(r *http.Request, proxied bool) string {
+	if proxied {
+		if host := forwardedParam(r, "host"); host != "" {
+			return host
+		}
+		if host := firstHeaderValue(r, "X-Forwarded-Host"); host != "" {
+			return host
+		}
+	}
+	return r.Host
+}
+
+// forwardedParam returns a parameter of the first element in the Forwarded header (RFC 7239).
+func forwardedParam(r *http.Request, key string) string {
+	var first, _, _ = strings.Cut(r.Header.Get("Forwarded"), ",")
+	for _, pair := range strings.Split(first, ";") {
+		var k, v, ok = strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && strings.EqualFold(k, key) {
+			return strings.Trim(v, `"`)
+		}
+	}
+	return ""
+}
+
+// firstHeaderValue returns the first of the comma separated values of the header.
+func firstHeaderValue(r *http.Request, key string) string {
+	var first, _, _ = strings.Cut(r.Header.Get(key), ",")
+	return strings.TrimSpace(first)
+}
+
 func GetIP(r *http.Request, proxied bool) string {
 	var ip string
 	if ip = r.Header.Get("X-Forwarded-For"); ip != "" && proxied {
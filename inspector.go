@@ -0,0 +1,193 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// InspectorMatch is the result of testing a URL in the inspector, see Mux.Inspector.
+type InspectorMatch struct {
+	Method    string     `json:"method"`
+	Host      string     `json:"host,omitempty"`
+	Path      string     `json:"path"`
+	Route     *RouteInfo `json:"route"`
+	Variables Variables  `json:"variables,omitempty"`
+	Attempts  []Attempt  `json:"attempts,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// InspectorPage is the data rendered by the inspector, see Mux.Inspector.
+type InspectorPage struct {
+	Routes []RouteInfo     `json:"routes"`
+	Match  *InspectorMatch `json:"match,omitempty"`
+}
+
+// Inspector returns a handler which renders the route table of the mux.
+//
+// The table is rendered as HTML, or as JSON if the format query parameter is "json"
+// or the request accepts application/json but not text/html.
+//
+// The method, host and path query parameters test which route Match would pick for a URL,
+// which variables it would extract and why the other routes were rejected, see Mux.Explain.
+// The path may also be a full URL.
+//
+//	m.Handle(mux.GET, "/debug/routes", m.Inspector(), "debug:routes")
+//
+// The route table reveals the internals of the application,
+// only mount the inspector behind authentication or on an internal listener.
+func (r *Mux) Inspector() Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var page = InspectorPage{
+			Routes: r.Routes(),
+			Match:  r.inspectMatch(req.URL.Query()),
+		}
+
+		if wantsJSON(req) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			var enc = json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			enc.Encode(page)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := inspectorTemplate.Execute(w, page); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// inspectMatch matches the URL in the query, or returns nil if no path was given.
+func (r *Mux) inspectMatch(query url.Values) *InspectorMatch {
+	var path = query.Get("path")
+	if path == "" {
+		return nil
+	}
+
+	var m = &InspectorMatch{
+		Method: strings.ToUpper(query.Get("method")),
+		Host:   query.Get("host"),
+	}
+	if m.Method == "" {
+		m.Method = GET
+	}
+
+	var u, err = url.Parse(path)
+	if err != nil {
+		m.Path = path
+		m.Error = err.Error()
+		return m
+	}
+	if u.Host != "" && m.Host == "" {
+		m.Host = u.Host
+	}
+	m.Path = u.EscapedPath()
+
+	var e = r.ExplainHost(m.Method, m.Host, m.Path)
+	if e.Route != nil {
+		var info = e.Route.Info()
+		m.Route = &info
+		m.Variables = e.Variables
+	}
+	m.Attempts = e.Attempts
+	return m
+}
+
+func wantsJSON(req *http.Request) bool {
+	switch req.URL.Query().Get("format") {
+	case "json":
+		return true
+	case "html":
+		return false
+	}
+	var accept = req.Header.Get("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
+
+var inspectorTemplate = template.Must(template.New("inspector").Funcs(template.FuncMap{
+	"methods": func() []string {
+		return []string{GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS}
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Routes</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.4em; text-align: left; vertical-align: top; }
+code, td { font-family: monospace; }
+.disabled { color: #a00; }
+.match { background: #f4f4f4; padding: 1em; margin-bottom: 2em; }
+</style>
+</head>
+<body>
+<h1>Routes</h1>
+<form class="match" method="get">
+<select name="method">
+{{- $method := "GET" }}{{ with .Match }}{{ $method = .Method }}{{ end }}
+{{- range $m := methods }}
+<option{{ if eq $m $method }} selected{{ end }}>{{ $m }}</option>
+{{- end }}
+</select>
+<input name="host" placeholder="host" value="{{ with .Match }}{{ .Host }}{{ end }}">
+<input name="path" placeholder="/path/" size="60" value="{{ with .Match }}{{ .Path }}{{ end }}">
+<button type="submit">Test</button>
+<a href="?format=json">JSON</a>
+{{- with .Match }}
+{{- if .Error }}
+<p>Invalid URL: {{ .Error }}</p>
+{{- else if .Route }}
+<p>{{ .Method }} {{ .Path }} matches <code>{{ .Route.Method }} {{ .Route.Pattern }}</code>{{ with .Route.Name }} named <code>{{ . }}</code>{{ end }}</p>
+{{- if .Variables }}
+<table>
+<tr><th>Variable</th><th>Value</th></tr>
+{{- range $k, $v := .Variables }}
+<tr><td>{{ $k }}</td><td>{{ range $i, $s := $v }}{{ if $i }}/{{ end }}{{ $s }}{{ end }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- else }}
+<p>{{ .Method }} {{ .Path }} does not match any route.</p>
+{{- end }}
+{{- if .Attempts }}
+<table>
+<tr><th>Method</th><th>Pattern</th><th>Name</th><th>Segments</th><th>Result</th></tr>
+{{- range .Attempts }}
+<tr>
+<td>{{ .Method }}</td>
+<td style="padding-left: {{ .Depth }}em">{{ .Pattern }}</td>
+<td>{{ .Name }}</td>
+<td>{{ .From }} &rarr; {{ .NextFrom }}</td>
+<td>{{ if .Matched }}matched{{ else }}{{ .Rejected }}{{ if ge .Segment 0 }} at segment {{ .Segment }}{{ with .SegmentValue }} ({{ . }}){{ end }}{{ end }}{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+{{- end }}
+{{- end }}
+</form>
+<table>
+<tr><th>Method</th><th>Pattern</th><th>Host</th><th>Name</th><th>Middleware</th><th>Handler</th></tr>
+{{- range .Routes }}
+<tr>
+<td>{{ .Method }}</td>
+<td>{{ .Pattern }}</td>
+<td>{{ .Host }}</td>
+<td>{{ .Name }}</td>
+<td{{ if .DisabledMiddleware }} class="disabled" title="Middleware is disabled for this route"{{ end }}>
+{{- if .DisabledMiddleware }}disabled<br>{{ end }}
+{{- range $i, $mw := .Chain }}{{ if $i }}<br>{{ end }}{{ $mw }}{{ end }}</td>
+<td>{{ .Handler }}{{ with .Source }}<br><small>{{ . }}</small>{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,59 @@
+package mux_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func introspectMiddleware(next mux.Handler) mux.Handler { return next }
+
+func TestInspector(t *testing.T) {
+	var m = newIntrospectMux()
+	m.Use(introspectMiddleware)
+	m.Find("about").RunsMiddleware(false)
+	m.Handle(mux.GET, "/debug/routes", m.Inspector(), "debug:routes")
+
+	var req = httptest.NewRequest(mux.GET, "/debug/routes?format=json&path=/api/users/42/", nil)
+	var rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, req)
+
+	var page mux.InspectorPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("Expected a JSON response, got %q: %v", rec.Body.String(), err)
+	}
+	if len(page.Routes) != 6 {
+		t.Fatalf("Expected 6 routes, got %d", len(page.Routes))
+	}
+
+	var user = page.Routes[1]
+	if len(user.Chain) != 2 || !strings.HasSuffix(user.Chain[0], "mux_test.introspectMiddleware") {
+		t.Fatalf("Expected the mux and route middleware in the chain, got %v", user.Chain)
+	}
+	if !page.Routes[2].DisabledMiddleware {
+		t.Fatalf("Expected middleware to be disabled for about")
+	}
+
+	if page.Match == nil || page.Match.Route == nil || page.Match.Route.Name != "api:user" || page.Match.Variables.Get("id") != "42" {
+		t.Fatalf("Expected /api/users/42/ to match api:user, got %+v", page.Match)
+	}
+
+	req = httptest.NewRequest(mux.GET, "/debug/routes?method=get&path=http://acme.example.com/", nil)
+	req.Header.Set("Accept", "text/html,application/json")
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, req)
+
+	var body = rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("Expected an HTML response, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	for _, s := range []string{"tenant:index", "&lt;&lt;id:int&gt;&gt;", "<td>tenant</td><td>acme</td>", `class="disabled"`} {
+		if !strings.Contains(body, s) {
+			t.Fatalf("Expected the page to contain %q:\n%s", s, body)
+		}
+	}
+}
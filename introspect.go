@@ -0,0 +1,204 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+)
+
+// RouteInfo describes a registered route, see Mux.Routes and Mux.ExportJSON.
+type RouteInfo struct {
+	// The fully qualified name of the route, and the names it is made up of.
+	Name     string   `json:"name,omitempty"`
+	PathName []string `json:"path_name,omitempty"`
+
+	Method  string `json:"method"`
+	Pattern string `json:"pattern"`
+	Host    string `json:"host,omitempty"`
+
+	// The number of middleware which run for the route, including those inherited from its parents.
+	Middleware    int `json:"middleware"`
+	PreMiddleware int `json:"pre_middleware"`
+
+	// The names of all middleware in the order they run, including those of the mux.
+	// The chain is not run when DisabledMiddleware is set.
+	Chain              []string `json:"chain,omitempty"`
+	DisabledMiddleware bool     `json:"disabled_middleware,omitempty"`
+
+	// The type of the handler, and the name and source location of the function which handles requests.
+	Handler  string `json:"handler,omitempty"`
+	Function string `json:"function,omitempty"`
+	Source   string `json:"source,omitempty"`
+
+	// Only set by ExportJSON, Routes returns a flat list.
+	Children []RouteInfo `json:"children,omitempty"`
+}
+
+// Walk calls fn for every route depth-first in registration order,
+// parents before their children. The depth of top-level routes is 0.
+//
+// Walking stops at the first error, which is returned.
+//
+// The routes are collected before fn is called, so fn may change the routes of the mux.
+func (r *Mux) Walk(fn func(rt *Route, depth int) error) error {
+	type visit struct {
+		route *Route
+		depth int
+	}
+
+	var visits []visit
+	var walk func(rt *Route, depth int)
+	walk = func(rt *Route, depth int) {
+		visits = append(visits, visit{rt, depth})
+		for _, child := range rt.Children {
+			walk(child, depth+1)
+		}
+	}
+
+	r.mu.Lock()
+	for _, rt := range r.routes {
+		walk(rt, 0)
+	}
+	r.mu.Unlock()
+
+	for _, v := range visits {
+		if err := fn(v.route, v.depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Routes returns a snapshot of all routes, in the order of Walk.
+func (r *Mux) Routes() []RouteInfo {
+	var routes = make([]RouteInfo, 0)
+	var walk func(rt *Route)
+	walk = func(rt *Route) {
+		routes = append(routes, rt.info())
+		for _, child := range rt.Children {
+			walk(child)
+		}
+	}
+
+	// The descriptions are built with the lock held, as the routes may be changed while serving.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rt := range r.routes {
+		walk(rt)
+	}
+	return routes
+}
+
+// ExportJSON writes the route tree as indented JSON to the writer.
+//
+// Routes are written in the order they were registered, so the output
+// only changes when the routes do.
+func (r *Mux) ExportJSON(w io.Writer) error {
+	var tree func(routes []*Route) []RouteInfo
+	tree = func(routes []*Route) []RouteInfo {
+		var infos = make([]RouteInfo, 0, len(routes))
+		for _, rt := range routes {
+			var info = rt.info()
+			info.Children = tree(rt.Children)
+			infos = append(infos, info)
+		}
+		return infos
+	}
+
+	r.mu.Lock()
+	var routes = tree(r.routes)
+	r.mu.Unlock()
+
+	var enc = json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(routes)
+}
+
+// Info returns a description of the route, without its children.
+func (r *Route) Info() RouteInfo {
+	defer r.lock()()
+	return r.info()
+}
+
+// info returns a description of the route, the caller holds the lock of the mux.
+func (r *Route) info() RouteInfo {
+	var info = RouteInfo{
+		Name:          r.FullName(),
+		PathName:      r.PathName(),
+		Method:        r.Method,
+		Middleware:    len(r.Middleware),
+		PreMiddleware: len(r.PreMiddleware),
+
+		DisabledMiddleware: r.DisabledMiddleware,
+	}
+
+	var chain = slices.Clone(r.PreMiddleware)
+	if r.ParentMux != nil {
+		chain = append(chain, r.ParentMux.middleware...)
+	}
+	chain = append(chain, r.Middleware...)
+	for _, mw := range chain {
+		var name = "?"
+		if fn := funcForValue(reflect.ValueOf(mw)); fn != nil {
+			name = fn.Name()
+		}
+		info.Chain = append(info.Chain, name)
+	}
+
+	if r.Path != nil {
+		info.Pattern = r.Path.String()
+	}
+
+	if host := r.HostPattern(); host != nil {
+		info.Host = host.String()
+	}
+
+	if r.Handler != nil {
+		info.Handler = fmt.Sprintf("%T", r.Handler)
+		if fn := handlerFunc(r.Handler); fn != nil {
+			var file, line = fn.FileLine(fn.Entry())
+			info.Function = fn.Name()
+			info.Source = fmt.Sprintf("%s:%d", file, line)
+		}
+	}
+
+	return info
+}
+
+// handlerFunc returns the function which handles requests for the handler.
+//
+// For handlers created from functions this is the function itself,
+// for other handlers it is their ServeHTTP method.
+func handlerFunc(h Handler) *runtime.Func {
+	var fn reflect.Value
+	switch h := h.(type) {
+	case *FuncHandler:
+		if h == nil {
+			return nil
+		}
+		fn = reflect.ValueOf(h.Func)
+	case http.HandlerFunc:
+		fn = reflect.ValueOf(h)
+	default:
+		var method, ok = reflect.TypeOf(h).MethodByName("ServeHTTP")
+		if !ok {
+			return nil
+		}
+		fn = method.Func
+	}
+	return funcForValue(fn)
+}
+
+func funcForValue(fn reflect.Value) *runtime.Func {
+	if !fn.IsValid() || fn.IsNil() {
+		return nil
+	}
+	return runtime.FuncForPC(fn.Pointer())
+}
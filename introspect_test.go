@@ -0,0 +1,104 @@
+package mux_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func introspectAbout(w http.ResponseWriter, r *http.Request) {}
+
+type introspectHandler struct{}
+
+func (h *introspectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func newIntrospectMux() *mux.Mux {
+	var m = mux.New()
+	var mw = func(next mux.Handler) mux.Handler { return next }
+	var api = m.Handle(mux.ANY, "/api/", nil, "api")
+	api.Use(mw)
+	api.Handle(mux.GET, "/users/<<id:int>>/", &introspectHandler{}, "user")
+	m.HandleFunc(mux.GET, "/about/", introspectAbout, "about")
+	m.Host("<<tenant>>.example.com", "tenant").Handle(mux.GET, "/", http.HandlerFunc(introspectAbout), "index")
+	return m
+}
+
+func TestWalk(t *testing.T) {
+	var m = newIntrospectMux()
+
+	var visited []string
+	m.Walk(func(rt *mux.Route, depth int) error {
+		visited = append(visited, strings.Repeat("-", depth)+rt.FullName())
+		return nil
+	})
+
+	var expected = []string{"api", "-api:user", "about", "tenant", "-tenant:index"}
+	if strings.Join(visited, " ") != strings.Join(expected, " ") {
+		t.Fatalf("Expected %v, got %v", expected, visited)
+	}
+
+	var stop = errors.New("stop")
+	var count int
+	var err = m.Walk(func(rt *mux.Route, depth int) error {
+		count++
+		if rt.Name == "user" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || count != 2 {
+		t.Fatalf("Expected Walk to stop with the error after 2 routes, got %v after %d", err, count)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	var m = newIntrospectMux()
+	var routes = m.Routes()
+	if len(routes) != 5 {
+		t.Fatalf("Expected 5 routes, got %d", len(routes))
+	}
+
+	var user = routes[1]
+	if user.Name != "api:user" || user.Method != mux.GET || user.Pattern != "/api/users/<<id:int>>" || user.Middleware != 1 {
+		t.Fatalf("Unexpected route info %+v", user)
+	}
+	if user.Handler != "*mux_test.introspectHandler" || !strings.HasSuffix(user.Function, "(*introspectHandler).ServeHTTP") {
+		t.Fatalf("Expected the handler type and method, got %q %q", user.Handler, user.Function)
+	}
+
+	for _, info := range []mux.RouteInfo{routes[2], routes[4]} {
+		if !strings.HasSuffix(info.Function, "mux_test.introspectAbout") || !strings.Contains(info.Source, "introspect_test.go:") {
+			t.Fatalf("Expected the source of introspectAbout, got %q %q", info.Function, info.Source)
+		}
+	}
+
+	if routes[4].Host != "<<tenant>>.example.com" {
+		t.Fatalf("Expected the host pattern to be inherited, got %q", routes[4].Host)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	var m = newIntrospectMux()
+
+	var a, b bytes.Buffer
+	if err := m.ExportJSON(&a); err != nil {
+		t.Fatal(err)
+	}
+	m.ExportJSON(&b)
+	if a.String() != b.String() {
+		t.Fatal("Expected the export to be stable")
+	}
+
+	var tree []mux.RouteInfo
+	if err := json.Unmarshal(a.Bytes(), &tree); err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 3 || len(tree[0].Children) != 1 || tree[0].Children[0].Name != "api:user" {
+		t.Fatalf("Expected a nested tree, got %s", a.String())
+	}
+}
@@ -0,0 +1,183 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RequestMatcher reports whether a request satisfies a condition of a route.
+//
+// Matchers are evaluated while matching, after the path matched.
+// If a matcher fails, matching continues with the next route, this allows
+// multiple routes with the same path to be told apart by I.E. their headers.
+type RequestMatcher func(r *http.Request) bool
+
+// MatcherFunc adds custom matchers to the route.
+//
+// Matchers apply to the children of the route as well.
+func (r *Route) MatcherFunc(matchers ...RequestMatcher) *Route {
+	defer r.lock()()
+	r.Matchers = append(r.Matchers, matchers...)
+	if r.ParentMux != nil {
+		r.ParentMux.routeChanged(r)
+		r.ParentMux.invalidate()
+	}
+	return r
+}
+
+// Headers adds matchers for header key/value pairs to the route.
+//
+// An empty value only requires the header to be present.
+func (r *Route) Headers(pairs ...string) *Route {
+	var matchers = make([]RequestMatcher, 0, len(pairs)/2)
+	for key, value := range keyValuePairs("Headers", pairs) {
+		matchers = append(matchers, MatchHeader(key, value))
+	}
+	return r.MatcherFunc(matchers...)
+}
+
+// HeadersRegexp adds matchers for header key/pattern pairs to the route.
+func (r *Route) HeadersRegexp(pairs ...string) *Route {
+	var matchers = make([]RequestMatcher, 0, len(pairs)/2)
+	for key, pattern := range keyValuePairs("HeadersRegexp", pairs) {
+		matchers = append(matchers, MatchHeaderRegexp(key, pattern))
+	}
+	return r.MatcherFunc(matchers...)
+}
+
+// Queries adds matchers for query parameter key/value pairs to the route.
+//
+// An empty value only requires the parameter to be present.
+func (r *Route) Queries(pairs ...string) *Route {
+	var matchers = make([]RequestMatcher, 0, len(pairs)/2)
+	for key, value := range keyValuePairs("Queries", pairs) {
+		matchers = append(matchers, MatchQuery(key, value))
+	}
+	return r.MatcherFunc(matchers...)
+}
+
+// Schemes adds a matcher for the scheme of the request to the route.
+func (r *Route) Schemes(schemes ...string) *Route {
+	return r.MatcherFunc(MatchScheme(schemes...))
+}
+
+// matches reports whether the request satisfies the matchers of the route and its parents.
+func (r *Route) matches(req *http.Request) bool {
+	for curr := r; curr != nil; curr = curr.Parent {
+		for _, matcher := range curr.Matchers {
+			if !matcher(req) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// routeMatchers returns the matchers of the route and its parents, in the order matches checks them.
+func (r *Route) routeMatchers() []RequestMatcher {
+	var matchers []RequestMatcher
+	for curr := r; curr != nil; curr = curr.Parent {
+		matchers = append(matchers, curr.Matchers...)
+	}
+	return matchers
+}
+
+// hasMatchers reports whether the route or any of its parents has matchers.
+func (r *Route) hasMatchers() bool {
+	for curr := r; curr != nil; curr = curr.Parent {
+		if len(curr.Matchers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchHeader matches requests where the header has the value.
+//
+// An empty value only requires the header to be present.
+func MatchHeader(key, value string) RequestMatcher {
+	key = http.CanonicalHeaderKey(key)
+	return func(r *http.Request) bool {
+		var values, ok = r.Header[key]
+		if !ok {
+			return false
+		}
+		if value == "" {
+			return true
+		}
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchHeaderRegexp matches requests where a value of the header matches the pattern.
+func MatchHeaderRegexp(key, pattern string) RequestMatcher {
+	var re = regexp.MustCompile(pattern)
+	key = http.CanonicalHeaderKey(key)
+	return func(r *http.Request) bool {
+		for _, v := range r.Header[key] {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchQuery matches requests where the query parameter has the value.
+//
+// An empty value only requires the parameter to be present.
+func MatchQuery(key, value string) RequestMatcher {
+	return func(r *http.Request) bool {
+		var values, ok = r.URL.Query()[key]
+		if !ok {
+			return false
+		}
+		if value == "" {
+			return true
+		}
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchScheme matches requests made with one of the schemes.
+func MatchScheme(schemes ...string) RequestMatcher {
+	var lower = make([]string, len(schemes))
+	for i, scheme := range schemes {
+		lower[i] = strings.ToLower(scheme)
+	}
+	return func(r *http.Request) bool {
+		var scheme = GetScheme(r)
+		for _, s := range lower {
+			if s == scheme {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// keyValuePairs turns a list of alternating keys and values into a sequence of pairs.
+func keyValuePairs(fn string, pairs []string) func(yield func(string, string) bool) {
+	if len(pairs)%2 != 0 {
+		panic(fmt.Sprintf("mux: %s requires an even number of arguments, got %d", fn, len(pairs)))
+	}
+	return func(yield func(string, string) bool) {
+		for i := 0; i < len(pairs); i += 2 {
+			if !yield(pairs[i], pairs[i+1]) {
+				return
+			}
+		}
+	}
+}
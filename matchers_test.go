@@ -0,0 +1,111 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestRequestMatchers(t *testing.T) {
+	var m = mux.New()
+	var write = func(name string) mux.Handler {
+		return mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, name)
+		})
+	}
+
+	m.Handle(mux.GET, "/users/", write("default"))
+	m.AddRoute(mux.NewRoute(mux.GET, "/users/", write("v2")).HeadersRegexp("Accept", `^application/vnd\.x\.v2\+json$`))
+	m.Handle(mux.POST, "/webhook/", write("push")).Headers("X-Event", "push")
+	m.Handle(mux.POST, "/webhook/", write("any-event")).Headers("X-Event", "")
+	m.Handle(mux.GET, "/search/", write("query")).Queries("q", "")
+	m.Handle(mux.GET, "/search/", write("page")).Queries("page", "2")
+	m.Handle(mux.GET, "/secure/", write("secure")).Schemes("https")
+	m.Handle(mux.GET, "/custom/", write("custom")).MatcherFunc(func(r *http.Request) bool {
+		return r.Header.Get("X-Custom") == "yes"
+	})
+
+	var parent = m.Handle(mux.ANY, "/internal/", nil).Headers("X-Internal", "1")
+	parent.Handle(mux.GET, "/stats/", write("stats"))
+
+	var tests = []struct {
+		method   string
+		target   string
+		headers  map[string]string
+		expected string
+		status   int
+	}{
+		{mux.GET, "/users/", nil, "default", http.StatusOK},
+		{mux.GET, "/users/", map[string]string{"Accept": "application/vnd.x.v2+json"}, "v2", http.StatusOK},
+		{mux.POST, "/webhook/", map[string]string{"X-Event": "push"}, "push", http.StatusOK},
+		{mux.POST, "/webhook/", map[string]string{"X-Event": "issue"}, "any-event", http.StatusOK},
+		{mux.POST, "/webhook/", nil, "", http.StatusNotFound},
+		{mux.GET, "/search/?q=go", nil, "query", http.StatusOK},
+		{mux.GET, "/search/?page=2", nil, "page", http.StatusOK},
+		{mux.GET, "/search/?page=3", nil, "", http.StatusNotFound},
+		{mux.GET, "https://example.com/secure/", nil, "secure", http.StatusOK},
+		{mux.GET, "http://example.com/secure/", nil, "", http.StatusNotFound},
+		{mux.GET, "/custom/", map[string]string{"X-Custom": "yes"}, "custom", http.StatusOK},
+		{mux.GET, "/custom/", nil, "", http.StatusNotFound},
+		{mux.GET, "/internal/stats/", map[string]string{"X-Internal": "1"}, "stats", http.StatusOK},
+		{mux.GET, "/internal/stats/", nil, "", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s-%s", test.method, test.target), func(t *testing.T) {
+			var req = httptest.NewRequest(test.method, test.target, nil)
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, req)
+			if w.Code != test.status {
+				t.Fatalf("Expected status %d, got %d", test.status, w.Code)
+			}
+			if test.status == http.StatusOK && w.Body.String() != test.expected {
+				t.Fatalf("Expected %q, got %q", test.expected, w.Body.String())
+			}
+		})
+	}
+
+	var req = httptest.NewRequest(mux.GET, "/users/", nil)
+	req.Header.Set("Accept", "application/vnd.x.v2+json")
+	if route, _ := m.MatchRequest(req); route == nil || len(route.Matchers) != 1 {
+		t.Fatalf("Expected MatchRequest to evaluate the matchers, got %v", route)
+	}
+
+	// Without a request, the matchers are not evaluated and the route without them is preferred.
+	if route, _ := m.Match(mux.GET, "/users/"); route == nil || len(route.Matchers) != 0 {
+		t.Fatalf("Expected Match to prefer the route without matchers, got %v", route)
+	}
+}
+
+func TestRequestMatchersReachable(t *testing.T) {
+	var m = mux.New()
+	m.StrictRoutes = true
+
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	m.Handle(mux.GET, "/users/", h)
+
+	// The matchers are set before the route is added, registering it must not panic.
+	m.AddRoute(mux.NewRoute(mux.GET, "/users/", h).Headers("Accept", "application/json"))
+	m.Handle(mux.GET, "/about/", h)
+
+	if route, _ := m.Match(mux.GET, "/about/"); route == nil {
+		t.Fatal("Expected a route for /about/")
+	}
+}
+
+func TestMatchSchemeKeepsArguments(t *testing.T) {
+	var schemes = []string{"HTTPS"}
+	var match = mux.MatchScheme(schemes...)
+	if schemes[0] != "HTTPS" {
+		t.Fatalf("Expected the schemes to be left unchanged, got %q", schemes[0])
+	}
+	if !match(httptest.NewRequest(mux.GET, "https://example.com/", nil)) {
+		t.Fatal("Expected the scheme to match regardless of case")
+	}
+}
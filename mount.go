@@ -0,0 +1,187 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var _ Resolver = (*mountedMux)(nil)
+
+// mount serves a handler mounted under a prefix, with the prefix stripped from the path.
+type mount struct {
+	handler http.Handler
+
+	// The name of the glob which captures the path below the prefix.
+	glob string
+}
+
+// mountedMux is a mux mounted under a prefix.
+//
+// The Mux itself cannot implement Resolver, its Match method finds a route by method and path.
+// The mounted mux resolves the names of its routes below the name of the mount instead.
+type mountedMux struct {
+	mount
+	mux *Mux
+}
+
+// Mount serves the handler for all requests below the prefix, with the prefix stripped from the path.
+//
+//	m.Mount("/admin/", admin, "admin")
+//
+// A request for `/admin/users/1` is served by the handler as a request for `/users/1`,
+// the prefix may contain variables, which are available through Vars in the middleware of the mux.
+// The mount matches any method and the whole path below the prefix,
+// requests which the handler does not know are left to the handler to answer.
+//
+// If the handler is a *Mux, its named routes can be found and reversed through the mux
+// by prefixing their names with the name of the mount, I.E. `admin:users:detail`.
+// The variables of the prefix come before the variables of the route in the mounted mux.
+func (r *Mux) Mount(prefix string, handler http.Handler, name ...string) *Route {
+	var glob = r.pathSyntax().Glob
+	var m = mount{handler: handler, glob: glob}
+
+	var h Handler = &m
+	if sub, ok := handler.(*Mux); ok {
+		h = &mountedMux{mount: m, mux: sub}
+	}
+
+	var path = strings.TrimSuffix(prefix, URL_DELIM) + URL_DELIM + glob
+	return r.Handle(ANY, path, h, name...)
+}
+
+func (m *mount) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// The captured segments are unescaped, the path is taken from the escaped path instead.
+	var escaped = req.URL.EscapedPath()
+	var segments = SplitPath(escaped)
+	var rest = segments[len(segments)-min(len(Vars(req)[m.glob]), len(segments)):]
+	var path = URL_DELIM + strings.Join(rest, URL_DELIM)
+	if len(rest) > 0 && strings.HasSuffix(escaped, URL_DELIM) {
+		path += URL_DELIM
+	}
+
+	var r2 = new(http.Request)
+	*r2 = *req
+	r2.URL = new(url.URL)
+	*r2.URL = *req.URL
+
+	var err error
+	if r2.URL.Path, err = url.PathUnescape(path); err != nil {
+		r2.URL.Path = path
+	}
+	r2.URL.RawPath = path
+	m.handler.ServeHTTP(w, r2)
+}
+
+// Match claims the path below the prefix, the mounted mux answers requests which it does not know.
+func (m *mountedMux) Match(vars Variables, path []string) (bool, Variables) {
+	vars[m.glob] = path
+	return true, vars
+}
+
+// Reverse appends the path of a route in the mounted mux to the prefix.
+//
+// The first variable is the name of the route, followed by its variables.
+// Without variables the prefix itself is returned, a []string is appended like the value of a glob.
+func (m *mountedMux) Reverse(baseURL string, variables ...interface{}) (string, error) {
+	if len(variables) == 0 {
+		return baseURL, nil
+	}
+
+	var name, ok = variables[0].(string)
+	if !ok {
+		var glob, _, err = reverseGlob(variables, 0, true)
+		if err != nil {
+			return "", err
+		}
+		return baseURL + escapeSegments(glob), nil
+	}
+
+	var path, err = m.mux.Reverse(name, variables[1:]...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(baseURL, URL_DELIM) + path, nil
+}
+
+// findMount returns the mounted mux for a name which is not registered on the mux itself,
+// together with the route it is mounted by and the name of the route in the mounted mux.
+func (r *Mux) findMount(name string) (*Route, *mountedMux, string) {
+	var idx = r.nameIndex()
+	for i := strings.LastIndex(name, NAME_SEPARATOR); i > 0; i = strings.LastIndex(name[:i], NAME_SEPARATOR) {
+		var rt = idx[name[:i]]
+		if rt == nil {
+			continue
+		}
+		if m, ok := rt.Handler.(*mountedMux); ok {
+			return rt, m, name[i+len(NAME_SEPARATOR):]
+		}
+	}
+	return nil, nil, ""
+}
+
+// mountVariables inserts the name of the route in the mounted mux
+// after the variables of the mount, see mountedMux.Reverse.
+func mountVariables(rt *Route, host bool, name string, variables []interface{}) []interface{} {
+	var n int
+	if p := rt.HostPattern(); p != nil && host {
+		for _, part := range p.Parts {
+			n += len(part.Names())
+		}
+	}
+
+	var parts = pathParts(rt.Path)
+	for _, part := range parts[:len(parts)-1] {
+		n += len(part.Names())
+	}
+	n = min(n, len(variables))
+
+	var values = make([]interface{}, 0, len(variables)+1)
+	values = append(values, variables[:n]...)
+	values = append(values, name)
+	return append(values, variables[n:]...)
+}
+
+// reverseMount builds the URL of a route in a mounted mux, with the variables by name.
+//
+// The variables of the mount are used for the prefix, the others for the route in the mounted mux.
+func (r *Mux) reverseMount(rt *Route, m *mountedMux, name string, variables map[string]any) (*url.URL, error) {
+	var prefix = make(map[string]any)
+	var rest = maps.Clone(variables)
+	var parts = pathParts(rt.Path)
+	if host := rt.HostPattern(); host != nil {
+		parts = append(host.Parts[:len(host.Parts):len(host.Parts)], parts...)
+	}
+	for _, part := range parts[:len(parts)-1] {
+		for _, name := range part.Names() {
+			if value, ok := rest[name]; ok {
+				prefix[name] = value
+				delete(rest, name)
+			}
+		}
+	}
+
+	var values, err = rt.orderVariables(prefix)
+	if err != nil {
+		return nil, err
+	}
+	u, err := rt.reverseURL(values...)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := m.mux.reverseNamed(name, rest)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		u.Host = sub.Host
+	}
+	u.Path = strings.TrimSuffix(u.Path, URL_DELIM) + sub.Path
+	u.RawPath = strings.TrimSuffix(u.RawPath, URL_DELIM) + sub.EscapedPath()
+	return u, nil
+}
@@ -0,0 +1,110 @@
+package mux_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func newMountMux() (*mux.Mux, *mux.Mux) {
+	var admin = mux.New()
+	var users = admin.Handle(mux.ANY, "/users/", nil, "users")
+	users.HandleFunc(mux.GET, "/<<id:int>>/", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "user "+mux.Vars(r).Get("id")+" at "+r.URL.Path+" tenant "+r.Header.Get("X-Tenant"))
+	}, "detail")
+
+	var m = mux.New()
+	m.Use(func(next mux.Handler) mux.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set("X-Tenant", mux.Vars(r).Get("tenant"))
+			next.ServeHTTP(w, r)
+		})
+	})
+	m.Mount("/<<tenant>>/admin/", admin, "admin")
+	return m, admin
+}
+
+func TestMountServe(t *testing.T) {
+	var m, _ = newMountMux()
+
+	var tests = []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/acme/admin/users/1/", http.StatusOK, "user 1 at /users/1/ tenant acme"},
+		{"/acme/admin/users/x/", http.StatusNotFound, ""},
+		{"/acme/other/users/1/", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		var w = httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(mux.GET, test.path, nil))
+		if w.Code != test.status {
+			t.Fatalf("%s: expected status %d, got %d", test.path, test.status, w.Code)
+		}
+		if test.body != "" && w.Body.String() != test.body {
+			t.Fatalf("%s: expected %q, got %q", test.path, test.body, w.Body.String())
+		}
+	}
+}
+
+func TestMountHandler(t *testing.T) {
+	var m = mux.New()
+	m.Mount("/static", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.URL.Path+" "+r.URL.RawPath)
+	}))
+
+	var tests = map[string]string{
+		"/static":            "/ /",
+		"/static/":           "/ /",
+		"/static/css/a.css":  "/css/a.css /css/a.css",
+		"/static/a%2Fb/c/":   "/a/b/c/ /a%2Fb/c/",
+		"/static/deep/dir/x": "/deep/dir/x /deep/dir/x",
+	}
+	for path, expected := range tests {
+		var w = httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(mux.POST, path, nil))
+		if w.Body.String() != expected {
+			t.Fatalf("%s: expected %q, got %q", path, expected, w.Body.String())
+		}
+	}
+}
+
+func TestMountReverse(t *testing.T) {
+	var m, admin = newMountMux()
+
+	if rt := m.Find("admin:users:detail"); rt == nil || rt != admin.Find("users:detail") {
+		t.Fatalf("Expected to find the route of the mounted mux, got %v", rt)
+	}
+	if m.Find("admin:users:missing") != nil {
+		t.Fatal("Expected no route for an unknown name")
+	}
+
+	var path, err = m.Reverse("admin:users:detail", "acme", 5)
+	if err != nil || path != "/acme/admin/users/5/" {
+		t.Fatalf("Expected /acme/admin/users/5/, got %q %v", path, err)
+	}
+
+	if path, err = m.Reverse("admin", "acme"); err != nil || path != "/acme/admin/" {
+		t.Fatalf("Expected /acme/admin/, got %q %v", path, err)
+	}
+
+	if _, err = m.Reverse("admin:users:missing", "acme"); err != mux.ErrRouteNotFound {
+		t.Fatalf("Expected ErrRouteNotFound, got %v", err)
+	}
+
+	path, err = m.ReverseMap("admin:users:detail", map[string]any{"tenant": "acme", "id": 5})
+	if err != nil || path != "/acme/admin/users/5/" {
+		t.Fatalf("Expected /acme/admin/users/5/, got %q %v", path, err)
+	}
+
+	var outer = mux.New()
+	outer.Mount("/v1", m, "v1")
+	if path, err = outer.Reverse("v1:admin:users:detail", "acme", 5); err != nil || path != "/v1/acme/admin/users/5/" {
+		t.Fatalf("Expected /v1/acme/admin/users/5/, got %q %v", path, err)
+	}
+}
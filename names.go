@@ -0,0 +1,88 @@
+package mux
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// FullName returns the fully qualified name of the route,
+// the names of its parents and its own name joined by the NAME_SEPARATOR.
+func (r *Route) FullName() string {
+	return strings.Join(r.PathName(), NAME_SEPARATOR)
+}
+
+// nameIndex returns the routes by their fully qualified name, building the index if needed.
+func (r *Mux) nameIndex() map[string]*Route {
+	if idx := r.names.Load(); idx != nil {
+		return *idx
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.publishNames()
+}
+
+// publishNames publishes a copy of the name index if needed, the caller holds mu.
+//
+// The published index is never modified, lookups do not have to lock.
+func (r *Mux) publishNames() map[string]*Route {
+	if idx := r.names.Load(); idx != nil {
+		return *idx
+	}
+
+	var idx = maps.Clone(r.buildNames())
+	r.names.Store(&idx)
+	return idx
+}
+
+// buildNames builds the name index from all routes if needed, the caller holds mu.
+func (r *Mux) buildNames() map[string]*Route {
+	if r.nameIdx == nil {
+		r.nameIdx = make(map[string]*Route)
+		for _, rt := range r.routes {
+			r.addNames(r.nameIdx, rt)
+		}
+	}
+	return r.nameIdx
+}
+
+// indexNames adds the route and its children to the name index, the caller holds mu.
+//
+// If a name is already in use, the route which was registered first keeps it.
+// If UniqueNames is set, this panics instead.
+func (r *Mux) indexNames(rt *Route) {
+	if r.nameIdx != nil {
+		r.addNames(r.nameIdx, rt)
+		return
+	}
+
+	// The index is built from all routes, rt included.
+	// Without UniqueNames this can wait until the first lookup.
+	if r.UniqueNames {
+		r.buildNames()
+	}
+}
+
+// resetNames discards the name index, it is rebuilt on the next lookup.
+// The caller holds mu.
+func (r *Mux) resetNames() {
+	r.nameIdx = nil
+	r.invalidate()
+}
+
+func (r *Mux) addNames(idx map[string]*Route, rt *Route) {
+	var name = rt.FullName()
+	if existing, ok := idx[name]; !ok {
+		idx[name] = rt
+	} else if existing != rt && rt.Name != "" && r.UniqueNames {
+		panic(fmt.Sprintf(
+			"mux: route %s %q uses the name %q, which is already used by %s %q",
+			rt.Method, rt.Path.String(), name, existing.Method, existing.Path.String(),
+		))
+	}
+
+	for _, child := range rt.Children {
+		r.addNames(idx, child)
+	}
+}
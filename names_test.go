@@ -0,0 +1,96 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestFindIndex(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+
+	var api = m.Handle(mux.ANY, "/api/", nil, "api")
+	var users = api.Handle(mux.GET, "/users/", h, "users")
+	m.Handle(mux.GET, "/about/", h, "about")
+
+	if m.Find("api:users") != users || users.FullName() != "api:users" {
+		t.Fatalf("Expected to find api:users, got %v", m.Find("api:users"))
+	}
+
+	// Routes added after the index was built are found.
+	var detail = users.Handle(mux.GET, "/<<id:int>>/", h, "detail")
+	if m.Find("api:users:detail") != detail {
+		t.Fatalf("Expected to find api:users:detail")
+	}
+
+	var sub = mux.NewRoute(mux.ANY, "/admin/", nil, "admin")
+	sub.Handle(mux.GET, "/stats/", h, "stats")
+	m.AddRoute(sub)
+	if m.Find("admin:stats") == nil {
+		t.Fatalf("Expected to find admin:stats")
+	}
+
+	// Removed routes are not.
+	api.RemoveChild(users)
+	if m.Find("api:users") != nil || m.Find("api:users:detail") != nil {
+		t.Fatalf("Expected api:users to be removed from the index")
+	}
+
+	m.RemoveByPath("/about/")
+	if m.Find("about") != nil {
+		t.Fatalf("Expected about to be removed from the index")
+	}
+	if m.Find("admin:stats") == nil {
+		t.Fatalf("Expected admin:stats to remain in the index")
+	}
+
+	m.ResetRoutes()
+	if m.Find("admin") != nil {
+		t.Fatalf("Expected the index to be empty")
+	}
+}
+
+func TestDuplicateNames(t *testing.T) {
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+
+	var m = mux.New()
+	var first = m.Handle(mux.GET, "/a/", h, "dup")
+	m.Handle(mux.GET, "/b/", h, "dup")
+	if m.Find("dup") != first {
+		t.Fatalf("Expected the first route to keep the name")
+	}
+
+	m = mux.New()
+	m.UniqueNames = true
+	m.Handle(mux.GET, "/a/", h, "a")
+	m.Handle(mux.GET, "/b/", h, "b")
+	m.Handle(mux.GET, "/c/", h)
+	m.Handle(mux.GET, "/d/", h)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected a duplicate name to panic")
+		}
+	}()
+	m.Handle(mux.ANY, "/x/", nil).Handle(mux.GET, "/a/", h, "a")
+	m.Handle(mux.ANY, "/y/", nil).Handle(mux.GET, "/a/", h, "a")
+}
+
+func BenchmarkFind(b *testing.B) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	for i := 0; i < 1000; i++ {
+		var parent = m.Handle(mux.ANY, fmt.Sprintf("/p%d/", i), nil, fmt.Sprintf("p%d", i))
+		parent.Handle(mux.GET, "/detail/", h, "detail")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if m.Find("p999:detail") == nil {
+			b.Fatal("Expected to find p999:detail")
+		}
+	}
+}
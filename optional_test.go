@@ -0,0 +1,100 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestOptionalParts(t *testing.T) {
+	var m = mux.New()
+	var write = func(name string) mux.Handler {
+		return mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %v", name, mux.Vars(r))
+		})
+	}
+	m.Handle(mux.GET, "/blog/<<page?1:int>>/", write("blog"), "blog")
+	m.Handle(mux.GET, "/archive/<<year?:int>>/<<month?:int>>/", write("archive"), "archive")
+	m.Handle(mux.GET, "/blog/latest/", write("latest"), "latest")
+
+	var tests = []struct {
+		path     string
+		expected string
+	}{
+		{"/blog/", "blog map[page:[1]]"},
+		{"/blog/3/", "blog map[page:[3]]"},
+		{"/blog/latest/", "latest map[]"},
+		{"/archive/", "archive map[]"},
+		{"/archive/2024/", "archive map[year:[2024]]"},
+		{"/archive/2024/5/", "archive map[month:[5] year:[2024]]"},
+		{"/blog/x/", ""},
+		{"/archive/2024/5/1/", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(mux.GET, test.path, nil))
+			if test.expected == "" {
+				if w.Code != http.StatusNotFound {
+					t.Fatalf("Expected a 404, got %d %q", w.Code, w.Body.String())
+				}
+				return
+			}
+			if w.Body.String() != test.expected {
+				t.Fatalf("Expected %q, got %q", test.expected, w.Body.String())
+			}
+		})
+	}
+
+	var reverse = []struct {
+		name      string
+		variables []interface{}
+		expected  string
+	}{
+		{"blog", nil, "/blog/"},
+		{"blog", []interface{}{2}, "/blog/2/"},
+		{"archive", nil, "/archive/"},
+		{"archive", []interface{}{2024}, "/archive/2024/"},
+		{"archive", []interface{}{2024, 5}, "/archive/2024/5/"},
+	}
+
+	for _, test := range reverse {
+		var path, err = m.Reverse(test.name, test.variables...)
+		if err != nil || path != test.expected {
+			t.Errorf("Reverse(%q, %v): expected %q, got %q (%v)", test.name, test.variables, test.expected, path, err)
+		}
+	}
+}
+
+func TestPathInfoOptional(t *testing.T) {
+	var info = mux.NewPathInfo(nil, "/blog/<<page?1:int>>/")
+	if info.String() != "/blog/<<page?1:int>>" {
+		t.Fatalf("Expected the path to round-trip, got %q", info.String())
+	}
+
+	var ok, _, vars = info.Match(mux.SplitPath("/blog/"), 0, nil)
+	if !ok || vars.Get("page") != "1" {
+		t.Fatalf("Expected the default to be used, got %v %v", ok, vars)
+	}
+
+	var panics = []string{
+		"/blog/<<page?>>/<<slug>>/",
+		"/blog/<<page?>>.html/",
+		"/blog/<<page?x:int>>/",
+		"/files/<<path...?>>",
+	}
+	for _, path := range panics {
+		t.Run(path, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected %q to panic", path)
+				}
+			}()
+			mux.NewPathInfo(nil, path)
+		})
+	}
+}
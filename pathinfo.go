@@ -9,6 +9,13 @@ import (
 
 // SplitPath splits a path into its parts.
 func SplitPath(path string) []string {
+	return appendSegments(make([]string, 0, strings.Count(path, URL_DELIM)+1), path)
+}
+
+// appendSegments appends the parts of the path to dst, like SplitPath.
+//
+// It does not allocate if dst has room for the parts.
+func appendSegments(dst []string, path string) []string {
 	// path = strings.ToLower(path)
 	var (
 		l = len(path)
@@ -22,17 +29,28 @@ func SplitPath(path string) []string {
 		e = l - 1
 	}
 	if s == e {
-		return []string{}
+		return dst
 	}
 
-	return strings.Split(path[s:e], URL_DELIM)
+	path = path[s:e]
+	for {
+		var i = strings.Index(path, URL_DELIM)
+		if i < 0 {
+			return append(dst, path)
+		}
+		dst = append(dst, path[:i])
+		path = path[i+len(URL_DELIM):]
+	}
 }
 
 // Global variables for use in the package.
 //
 // These are exported so that they can be changed if needed.
+// VARIABLE_DELIMS, CONVERTER_DELIM and GLOB make up the DefaultPathSyntax,
+// set Mux.Syntax to use a different syntax for the routes of a single Mux.
 var (
 	VARIABLE_DELIMS = []string{"<<", ">>"}
+	CONVERTER_DELIM = ":"
 	URL_DELIM       = "/"
 	GLOB            = "*"
 )
@@ -50,6 +68,9 @@ type PathInfo struct {
 	Parent   *PathInfo
 	Path     []*PathPart
 	Resolver Resolver
+
+	// Whether the path was defined with a trailing slash.
+	TrailingSlash bool
 }
 
 // WithParent returns a new PathInfo with the given parent.
@@ -58,44 +79,71 @@ func (p *PathInfo) WithParent(parent *PathInfo) *PathInfo {
 		return p
 	}
 
-	if parent.IsGlob {
+	if parent.IsGlob || parent.hasGlob() {
 		panic(fmt.Sprintf("parent path of %q cannot be a glob", p.String()))
 	}
 
+	if parent.hasOptional() {
+		panic(fmt.Sprintf("parent path of %q cannot have optional parts", p.String()))
+	}
+
 	return &PathInfo{
 		IsGlob:   p.IsGlob,
 		Parent:   parent,
 		Path:     p.Path,
 		Resolver: p.Resolver,
+
+		TrailingSlash: p.TrailingSlash,
 	}
 }
 
-// String returns a string representation of the path.
-func (p *PathInfo) String() string {
-	var b strings.Builder
-	var totalLen int
-	var delimLen = len(VARIABLE_DELIMS[0]) + len(VARIABLE_DELIMS[1])
+// hasGlob reports whether any part of the path is a glob, including globs followed by other parts.
+func (p *PathInfo) hasGlob() bool {
 	for _, part := range p.Path {
-		totalLen += len(part.Part) + 1
-		if part.IsVariable {
-			totalLen += delimLen
+		if part.IsGlob {
+			return true
 		}
 	}
+	return false
+}
 
+// storesVariables reports whether matching the path stores any variables.
+func (p *PathInfo) storesVariables() bool {
+	for _, part := range p.Path {
+		if part.IsVariable || part.IsGlob {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOptional reports whether the path ends in optional parts.
+func (p *PathInfo) hasOptional() bool {
+	return len(p.Path) > 0 && p.Path[len(p.Path)-1].Optional
+}
+
+// trailingSlash reports whether the full path ends with a slash.
+//
+// This is decided by the last path in the chain which has any parts.
+func (p *PathInfo) trailingSlash() bool {
+	for pt := p; pt != nil; pt = pt.Parent {
+		if len(pt.Path) > 0 {
+			return pt.TrailingSlash
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the path.
+func (p *PathInfo) String() string {
+	var b strings.Builder
 	if p.Parent != nil {
 		b.WriteString(p.Parent.String())
 	}
 
-	b.Grow(totalLen)
 	b.WriteString(URL_DELIM)
 	for i, part := range p.Path {
-		if part.IsVariable {
-			b.WriteString(VARIABLE_DELIMS[0])
-		}
-		b.WriteString(part.Part)
-		if part.IsVariable {
-			b.WriteString(VARIABLE_DELIMS[1])
-		}
+		b.WriteString(part.String())
 		if i < len(p.Path)-1 {
 			b.WriteString(URL_DELIM)
 		}
@@ -104,12 +152,73 @@ func (p *PathInfo) String() string {
 	return b.String()
 }
 
+// pathParts returns all parts of the path, including those of the parents.
+func pathParts(p *PathInfo) []*PathPart {
+	var chain = make([]*PathInfo, 0)
+	var total int
+	for pt := p; pt != nil; pt = pt.Parent {
+		chain = append(chain, pt)
+		total += len(pt.Path)
+	}
+	slices.Reverse(chain)
+
+	var parts = make([]*PathPart, 0, total)
+	for _, pt := range chain {
+		parts = append(parts, pt.Path...)
+	}
+	return parts
+}
+
 // PathPart contains information about a part of a path.
 type PathPart struct {
 	Part       string
 	IsVariable bool
 	IsGlob     bool
-	// Validators []func(string) bool
+
+	// The literal and variable pieces of a segment which mixes
+	// text and variables, I.E. `<<name>>.<<ext>>`.
+	//
+	// Part holds the segment as written in the path.
+	Pieces []*PathPart
+
+	// The name of the converter used to validate the variable, if any.
+	Converter string
+
+	// The regular expression the variable must match, if any.
+	//
+	// The pattern is anchored and compiled once, when the PathInfo is created.
+	Pattern string
+
+	// Whether the variable may be left out of the path, I.E. `<<page?>>`.
+	// Only the last parts of a path can be optional.
+	Optional bool
+
+	// The value stored in the Variables when an optional part is left out, I.E. `<<page?1>>`.
+	// If empty, the variable is not stored.
+	Default string
+
+	converter Converter
+	syntax    *PathSyntax
+}
+
+// Constraint returns the converter name or pattern of the part, as written in the path.
+func (p *PathPart) Constraint() string {
+	if p.Pattern != "" {
+		return p.Pattern
+	}
+	return p.Converter
+}
+
+// Name returns the key under which the value of this part is stored in the Variables.
+func (p *PathPart) Name() string {
+	return p.Part
+}
+
+// Validate reports whether the value satisfies the converter of this part.
+//
+// Parts without a converter accept any value.
+func (p *PathPart) Validate(value string) bool {
+	return p.converter == nil || p.converter.Match(value)
 }
 
 // Match matches a path to this path.
@@ -121,7 +230,12 @@ func (p *PathInfo) Match(path []string, from int, variables Variables) (matched
 	var i = from
 
 	// Match each part defined on this PathInfo only, parent paths are matched by the caller.
-	for _, part := range p.Path {
+	for k, part := range p.Path {
+		// A glob followed by other parts eats at least one segment, and leaves enough for the parts after it.
+		if part.IsGlob && k < len(p.Path)-1 {
+			return p.matchGlob(path, i, k, variables)
+		}
+
 		// If this part is a terminal glob, it eats the rest (possibly zero)
 		if part.IsGlob {
 			if p.Resolver != nil {
@@ -141,13 +255,16 @@ func (p *PathInfo) Match(path []string, from int, variables Variables) (matched
 				}
 				return ok, len(path), variables
 			} else {
-				// Capture the remainder as GLOB
+				if !part.Validate(strings.Join(path[i:], URL_DELIM)) {
+					return false, -1, nil
+				}
+				// Capture the remainder as GLOB, or under the name of the variable.
 				if variables == nil {
 					variables = Variables{
-						GLOB: path[i:],
+						part.Name(): path[i:],
 					}
 				} else {
-					variables[GLOB] = path[i:]
+					variables[part.Name()] = path[i:]
 				}
 			}
 			// Glob ends the pattern
@@ -156,6 +273,11 @@ func (p *PathInfo) Match(path []string, from int, variables Variables) (matched
 			break
 		}
 
+		// The remaining parts are optional, and left out of the path.
+		if i >= len(path) && part.Optional {
+			return true, i, p.withDefaults(k, variables)
+		}
+
 		// Need a segment to match this literal/variable
 		if i >= len(path) {
 			// Not enough segments -> not even a partial for children,
@@ -166,13 +288,16 @@ func (p *PathInfo) Match(path []string, from int, variables Variables) (matched
 		seg := path[i]
 		switch {
 		case part.IsVariable:
-			if seg == "" {
+			var values, ok = part.capture(seg, nil, false)
+			if !ok {
 				return false, -1, nil
 			}
 			if variables == nil {
 				variables = make(Variables)
 			}
-			variables[part.Part] = append(variables[part.Part], seg)
+			for j, name := range part.Names() {
+				variables[name] = append(variables[name], values[j])
+			}
 		case part.Part != seg:
 			return false, -1, nil
 		}
@@ -189,8 +314,71 @@ func (p *PathInfo) Match(path []string, from int, variables Variables) (matched
 	return false, i, variables
 }
 
+// withDefaults stores the defaults of the optional parts starting at index k in the variables.
+func (p *PathInfo) withDefaults(k int, variables Variables) Variables {
+	for _, part := range p.Path[k:] {
+		if part.Default == "" {
+			continue
+		}
+		if variables == nil {
+			variables = make(Variables)
+		}
+		variables[part.Part] = []string{part.Default}
+	}
+	return variables
+}
+
+// matchGlob matches the glob at index k of the path and the parts after it, starting at segment from.
+//
+// The glob is greedy, the longest remainder is tried first; if the parts
+// after the glob do not match, it backtracks and gives up a segment.
+// A path with a glob cannot have children, so it only matches the full path.
+func (p *PathInfo) matchGlob(path []string, from, k int, variables Variables) (bool, int, Variables) {
+	var glob, suffix = p.Path[k], p.Path[k+1:]
+
+outer:
+	for end := len(path) - len(suffix); end > from; end-- {
+		if !glob.Validate(strings.Join(path[from:end], URL_DELIM)) {
+			continue
+		}
+
+		var vars = maps.Clone(variables)
+		if vars == nil {
+			vars = make(Variables)
+		}
+		vars[glob.Name()] = path[from:end]
+
+		// The parts after the glob cannot contain another glob, each matches a single segment.
+		for j, part := range suffix {
+			var seg = path[end+j]
+			if !part.IsVariable {
+				if part.Part != seg {
+					continue outer
+				}
+				continue
+			}
+
+			var values, ok = part.capture(seg, nil, false)
+			if !ok {
+				continue outer
+			}
+			for n, name := range part.Names() {
+				vars[name] = append(vars[name], values[n])
+			}
+		}
+
+		return true, len(path), vars
+	}
+
+	return false, -1, nil
+}
+
 // Reverse returns the path with the variables replaced.
 //
+// Variables are escaped, so the path can be used in a URL as is.
+// The value of a glob may span multiple segments, only the text
+// between its slashes is escaped.
+//
 // If a variable is not found, this function will error.
 func (p *PathInfo) Reverse(variables ...interface{}) (string, error) {
 	var (
@@ -218,33 +406,38 @@ func (p *PathInfo) Reverse(variables ...interface{}) (string, error) {
 			seenParts++
 
 			if part.IsGlob && len(variables) >= varIndex && pathIndex == len(path)-1 {
+				var last = seenParts == totalParts
+
 				// The resolver can take over when it is a GLOB
-				if pathObject.Resolver != nil && pathIndex == len(path)-1 {
+				if pathObject.Resolver != nil && last {
 					return pathObject.Resolver.Reverse(b.String(), variables[varIndex:]...)
 				}
 
-				for _, v := range variables[varIndex:] {
-					varIndex++
-
-					b.WriteString(fmt.Sprint(v))
+				var glob string
+				var err error
+				if glob, varIndex, err = reverseGlob(variables, varIndex, last); err != nil {
+					return "", err
+				}
 
-					if varIndex < len(variables) {
-						b.WriteString(URL_DELIM)
-					}
+				if !part.Validate(glob) {
+					return "", ErrInvalidVariable
 				}
 
-				break
+				b.WriteString(escapeSegments(glob))
+				if !last {
+					b.WriteString(URL_DELIM)
+				}
+				continue
 			}
 
-			if part.IsVariable && varIndex >= len(variables) {
-				return "", ErrNotEnoughVariables
+			// Optional parts without a value are left out, they can only be followed by other optional parts.
+			if part.Optional && varIndex >= len(variables) {
+				break
 			}
 
-			if part.IsVariable {
-				b.WriteString(fmt.Sprint(variables[varIndex]))
-				varIndex++
-			} else {
-				b.WriteString(part.Part)
+			var err error
+			if varIndex, err = part.reverse(&b, variables, varIndex); err != nil {
+				return "", err
 			}
 
 			b.WriteString(URL_DELIM)
@@ -258,32 +451,113 @@ func (p *PathInfo) Reverse(variables ...interface{}) (string, error) {
 	return b.String(), nil
 }
 
+// reverseGlob returns the value for a glob, consuming variables starting at index.
+//
+// A slice of strings is used as the segments of the glob.
+// Otherwise a terminal glob consumes all remaining variables as its segments,
+// and a glob followed by other parts consumes a single variable, which cannot be empty.
+func reverseGlob(variables []interface{}, index int, last bool) (string, int, error) {
+	if index < len(variables) {
+		if segments, ok := variables[index].([]string); ok {
+			if !last && len(segments) == 0 {
+				return "", index, ErrInvalidVariable
+			}
+			return strings.Join(segments, URL_DELIM), index + 1, nil
+		}
+	}
+
+	if !last {
+		if index >= len(variables) {
+			return "", index, ErrNotEnoughVariables
+		}
+		var value = fmt.Sprint(variables[index])
+		if value == "" {
+			return "", index, ErrInvalidVariable
+		}
+		return value, index + 1, nil
+	}
+
+	var glob strings.Builder
+	for _, v := range variables[index:] {
+		index++
+
+		glob.WriteString(fmt.Sprint(v))
+
+		if index < len(variables) {
+			glob.WriteString(URL_DELIM)
+		}
+	}
+	return glob.String(), index, nil
+}
+
 // NewPathInfo creates a new PathInfo object from a path string.
 //
-// The path string can contain variables,
-// which are defined by the text between the VARIABLE_DELIMS.
+// The path string can contain variables, which are defined by the text between
+// the variable delimiters of the route's Mux syntax, see PathSyntax.
+// The examples below use the default syntax.
+// A segment can hold multiple variables mixed with literal text, I.E. `/files/<<name>>.<<ext>>`.
+//
+// A variable can specify a converter after the CONVERTER_DELIM, I.E. `<<id:int>>`.
+// The converter is looked up on the route's Mux, or in the built-in converters.
+// Anything which is not a valid converter name is compiled as a regular expression
+// the variable must fully match, I.E. `<<year:[0-9]{4}>>` or `<<lang:en|nl|de>>`.
+// A pattern which could be mistaken for a converter name can be wrapped in a group, I.E. `<<lang:(en)>>`.
+// The `path` converter captures the remainder of the path.
+//
+// A glob captures multiple segments, it is written as `*` or as a named catch-all, I.E. `<<path...>>`.
+// Unnamed globs are stored in the Variables under the glob itself.
+// A glob may be followed by other parts, I.E. `/repos/<<repo...>>/blob/<<ref>>`,
+// it then captures at least one segment, and the route cannot have children.
 //
-// This function will panic if the GLOB is not the last part of the path.
+// The last variables of a path can be optional, I.E. `/blog/<<page?>>`.
+// An optional variable can have a default, which is stored in the Variables
+// when it is left out of the path, I.E. `/blog/<<page?1:int>>`.
+// A path with optional parts cannot have children.
+//
+// This function will panic if the path contains more than one glob,
+// or if an optional part is followed by a part which is not optional.
 func NewPathInfo(rt *Route, path string) *PathInfo {
 	var parts = SplitPath(path)
 	var info = &PathInfo{
 		Path: make([]*PathPart, 0, len(parts)),
+
+		TrailingSlash: len(path) > 1 && strings.HasSuffix(path, URL_DELIM),
 	}
 
-	for i, part := range parts {
-		var pathPart = &PathPart{Part: part}
+	var m *Mux
+	if rt != nil {
+		m = rt.ParentMux
+	}
+	var syntax = m.pathSyntax()
 
-		// Check if this part is a variable
-		if strings.HasPrefix(part, VARIABLE_DELIMS[0]) &&
-			strings.HasSuffix(part, VARIABLE_DELIMS[1]) {
+	var globs int
+	for i, part := range parts {
+		var pathPart = parseSegment(m, part)
 
-			pathPart.IsVariable = true
-			pathPart.Part = part[len(VARIABLE_DELIMS[0]) : len(part)-len(VARIABLE_DELIMS[1])]
-		} else if part == GLOB && i == len(parts)-1 {
-			info.IsGlob = true
+		// Check if this part captures multiple segments
+		if pathPart.Converter == PathConverter || !pathPart.IsVariable && part == syntax.Glob {
 			pathPart.IsGlob = true
-		} else if part == GLOB {
-			panic("glob must be the last part of the path, using glob specifies an unknown path length")
+		}
+
+		if pathPart.Optional && (pathPart.IsGlob || pathPart.IsMixed()) {
+			panic(fmt.Sprintf("optional part %q in path %q must be a single variable", part, path))
+		}
+
+		if !pathPart.Optional && len(info.Path) > 0 && info.Path[len(info.Path)-1].Optional {
+			panic(fmt.Sprintf("optional parts can only be followed by other optional parts in path %q", path))
+		}
+
+		if pathPart.Optional && pathPart.Default != "" && !pathPart.Validate(pathPart.Default) {
+			panic(fmt.Sprintf("default %q of %q in path %q does not satisfy its converter", pathPart.Default, pathPart.Part, path))
+		}
+
+		if pathPart.IsGlob {
+			if globs++; globs > 1 {
+				panic(fmt.Sprintf("path %q contains more than one glob, the length of each glob would be unknown", path))
+			}
+			if i == len(parts)-1 {
+				info.IsGlob = true
+			}
 		}
 		info.Path = append(info.Path, pathPart)
 	}
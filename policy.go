@@ -0,0 +1,122 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// TrailingSlashPolicy decides how the trailing slash of a request path is treated.
+type TrailingSlashPolicy int
+
+const (
+	// Paths match regardless of a trailing slash, this is the default.
+	TrailingSlashLenient TrailingSlashPolicy = iota
+
+	// Paths only match routes which agree on the trailing slash,
+	// `/a/b` only matches a route defined as `/a/b` and `/a/b/` only matches `/a/b/`.
+	TrailingSlashStrict
+
+	// Requests for a path without a trailing slash are redirected to the path with a trailing slash.
+	TrailingSlashRedirectAdd
+
+	// Requests for a path with a trailing slash are redirected to the path without it.
+	TrailingSlashRedirectRemove
+)
+
+// CleanPath returns the canonical form of the path.
+//
+// Repeated slashes are collapsed, `.` and `..` segments are resolved
+// and a trailing slash is kept if the path had one.
+func CleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	var cleaned = path.Clean("/" + p)
+	if cleaned != "/" && strings.HasSuffix(p, "/") {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// hasTrailingSlash reports whether a request path ends with a slash.
+func hasTrailingSlash(path string) bool {
+	return len(path) > 1 && strings.HasSuffix(path, URL_DELIM)
+}
+
+// newMatchInput creates the input to match routes against, applying the settings of the mux.
+//
+// The segments of the path are stored in the buffer of the state.
+func (r *Mux) newMatchInput(st *matchState, method, host, path string, req *http.Request) matchInput {
+	st.segments = unescapeSegments(appendSegments(st.segments[:0], path))
+	return matchInput{
+		method:      method,
+		host:        host,
+		path:        st.segments,
+		req:         req,
+		slash:       hasTrailingSlash(path),
+		strictSlash: r.TrailingSlash == TrailingSlashStrict,
+		fold:        r.IgnoreCase,
+	}
+}
+
+// canonicalPath returns the path the request should be redirected to, if any.
+//
+// It is called after the route has been matched, so only
+// requests for existing routes are redirected.
+func (r *Mux) canonicalPath(path string, c *treeCandidate) (string, bool) {
+	var slash = hasTrailingSlash(path)
+	if path != "/" && !c.leaf.glob() {
+		switch r.TrailingSlash {
+		case TrailingSlashRedirectAdd:
+			slash = true
+		case TrailingSlashRedirectRemove:
+			slash = false
+		}
+	}
+
+	// Routes written in mixed case are served for their lowercase path.
+	if r.CaseRedirect && c.folded {
+		if canonical := c.canonicalPath(slash); canonical != path {
+			return canonical, true
+		}
+	}
+
+	switch {
+	case slash && !hasTrailingSlash(path):
+		return path + URL_DELIM, true
+	case !slash && hasTrailingSlash(path):
+		return strings.TrimSuffix(path, URL_DELIM), true
+	}
+	return "", false
+}
+
+// redirectHandler redirects to the escaped path, keeping the query of the request.
+//
+// GET and HEAD requests are redirected with a 301, other methods with a 308
+// so clients do not change the method or drop the body.
+func redirectHandler(path string) http.HandlerFunc {
+	// Never redirect to a protocol-relative URL, which could point to another host.
+	if strings.HasPrefix(path, "//") {
+		path = URL_DELIM + strings.TrimLeft(path, URL_DELIM)
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		var location = path
+		if req.URL.RawQuery != "" {
+			location += "?" + req.URL.RawQuery
+		}
+
+		var status = http.StatusPermanentRedirect
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+
+		w.Header().Set("Location", location)
+		w.WriteHeader(status)
+	}
+}
@@ -0,0 +1,162 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestCleanPath(t *testing.T) {
+	var tests = map[string]string{
+		"":             "/",
+		"/":            "/",
+		"a/b":          "/a/b",
+		"//a//b/":      "/a/b/",
+		"/a/./b/../c":  "/a/c",
+		"/a/b/..":      "/a",
+		"/../a/":       "/a/",
+		"/a/b/../../":  "/",
+		"/a/b/c/./../": "/a/b/",
+	}
+
+	for path, expected := range tests {
+		if cleaned := mux.CleanPath(path); cleaned != expected {
+			t.Errorf("CleanPath(%q): expected %q, got %q", path, expected, cleaned)
+		}
+	}
+}
+
+func newPolicyMux(policy mux.TrailingSlashPolicy) *mux.Mux {
+	var m = mux.New()
+	m.TrailingSlash = policy
+	var write = func(name string) mux.Handler {
+		return mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, name)
+		})
+	}
+	m.Handle(mux.GET, "/", write("root"))
+	m.Handle(mux.GET, "/users", write("users"))
+
+	// Only the strict policy tells the routes apart, otherwise the second route is shadowed.
+	if policy == mux.TrailingSlashStrict {
+		m.Handle(mux.GET, "/users/", write("users/"))
+	}
+	m.Handle(mux.ANY, "/posts/<<id:int>>/", write("post"))
+	m.Handle(mux.GET, "/static/*", write("static"))
+	return m
+}
+
+func TestTrailingSlashPolicy(t *testing.T) {
+	var tests = []struct {
+		policy   mux.TrailingSlashPolicy
+		method   string
+		target   string
+		status   int
+		expected string
+	}{
+		{mux.TrailingSlashLenient, mux.GET, "/users", http.StatusOK, "users"},
+		{mux.TrailingSlashLenient, mux.GET, "/users/", http.StatusOK, "users"},
+		{mux.TrailingSlashLenient, mux.GET, "/posts/1", http.StatusOK, "post"},
+
+		{mux.TrailingSlashStrict, mux.GET, "/", http.StatusOK, "root"},
+		{mux.TrailingSlashStrict, mux.GET, "/users", http.StatusOK, "users"},
+		{mux.TrailingSlashStrict, mux.GET, "/users/", http.StatusOK, "users/"},
+		{mux.TrailingSlashStrict, mux.GET, "/posts/1/", http.StatusOK, "post"},
+		{mux.TrailingSlashStrict, mux.GET, "/posts/1", http.StatusNotFound, ""},
+		{mux.TrailingSlashStrict, mux.GET, "/static/css/", http.StatusOK, "static"},
+		{mux.TrailingSlashStrict, mux.GET, "/static/css", http.StatusOK, "static"},
+
+		{mux.TrailingSlashRedirectAdd, mux.GET, "/", http.StatusOK, "root"},
+		{mux.TrailingSlashRedirectAdd, mux.GET, "/posts/1/", http.StatusOK, "post"},
+		{mux.TrailingSlashRedirectAdd, mux.GET, "/posts/1?a=b", http.StatusMovedPermanently, "/posts/1/?a=b"},
+		{mux.TrailingSlashRedirectAdd, mux.POST, "/posts/1", http.StatusPermanentRedirect, "/posts/1/"},
+		{mux.TrailingSlashRedirectAdd, mux.GET, "/static/css", http.StatusOK, "static"},
+		{mux.TrailingSlashRedirectAdd, mux.GET, "/missing", http.StatusNotFound, ""},
+
+		{mux.TrailingSlashRedirectRemove, mux.GET, "/posts/1", http.StatusOK, "post"},
+		{mux.TrailingSlashRedirectRemove, mux.GET, "/posts/1/", http.StatusMovedPermanently, "/posts/1"},
+		{mux.TrailingSlashRedirectRemove, mux.HEAD, "/posts/1/", http.StatusMovedPermanently, "/posts/1"},
+		{mux.TrailingSlashRedirectRemove, mux.GET, "/static/css/", http.StatusOK, "static"},
+	}
+
+	var muxes = make(map[mux.TrailingSlashPolicy]*mux.Mux)
+	for _, test := range tests {
+		var m, ok = muxes[test.policy]
+		if !ok {
+			m = newPolicyMux(test.policy)
+			muxes[test.policy] = m
+		}
+
+		t.Run(fmt.Sprintf("%d-%s-%s", test.policy, test.method, test.target), func(t *testing.T) {
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(test.method, test.target, nil))
+			if w.Code != test.status {
+				t.Fatalf("Expected status %d, got %d", test.status, w.Code)
+			}
+
+			switch test.status {
+			case http.StatusOK:
+				if w.Body.String() != test.expected {
+					t.Fatalf("Expected %q, got %q", test.expected, w.Body.String())
+				}
+			case http.StatusMovedPermanently, http.StatusPermanentRedirect:
+				if location := w.Header().Get("Location"); location != test.expected {
+					t.Fatalf("Expected a redirect to %q, got %q", test.expected, location)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanPathRedirect(t *testing.T) {
+	var m = newPolicyMux(mux.TrailingSlashLenient)
+	m.CleanPath = true
+
+	var tests = []struct {
+		path     string
+		query    string
+		status   int
+		location string
+	}{
+		{"/users/", "", http.StatusOK, ""},
+		{"/a/../users/", "", http.StatusMovedPermanently, "/users/"},
+		{"/posts//1/", "x=1", http.StatusMovedPermanently, "/posts/1/?x=1"},
+		{"/posts/./1", "", http.StatusMovedPermanently, "/posts/1"},
+		{"//evil.com/", "", http.StatusMovedPermanently, "/evil.com/"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			// Set the path directly, the request line would be cleaned by the parser.
+			var req = httptest.NewRequest(mux.GET, "/", nil)
+			req.URL.Path, req.URL.RawQuery = test.path, test.query
+
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, req)
+			if w.Code != test.status {
+				t.Fatalf("Expected status %d, got %d", test.status, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != test.location {
+				t.Fatalf("Expected a redirect to %q, got %q", test.location, location)
+			}
+		})
+	}
+}
+
+func TestStrictSlashReachable(t *testing.T) {
+	var m = mux.New()
+	m.StrictRoutes = true
+	m.TrailingSlash = mux.TrailingSlashStrict
+
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	m.Handle(mux.GET, "/users", h)
+	m.Handle(mux.GET, "/users/", h)
+
+	// Both routes can be matched in strict mode, compiling must not panic.
+	if route, _ := m.Match(mux.GET, "/users/"); route == nil || !route.Path.TrailingSlash {
+		t.Fatalf("Expected the /users/ route, got %v", route)
+	}
+}
@@ -0,0 +1,131 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"slices"
+	"sync"
+)
+
+// matchState holds the buffers used to match a request.
+//
+// States are reused through the matchPool, so matching does not
+// allocate once the buffers have grown to fit the routes.
+type matchState struct {
+	segments   []string
+	captures   []string // the captures of the branch of the tree which is being walked
+	arena      []string // the captures of the candidates, see treeCandidate.captures
+	candidates []treeCandidate
+
+	// Whether the variables are stored in vars and values, which are reused
+	// with the state. Otherwise a new map is made for the caller to keep.
+	reuse  bool
+	vars   Variables
+	values []string
+}
+
+// maxPooledCaptures limits the size of the buffers which are put back into the pool,
+// so a single request with a huge path does not keep its buffers alive.
+const maxPooledCaptures = 1024
+
+var matchPool = sync.Pool{
+	New: func() any {
+		return &matchState{
+			captures: make([]string, 0, 16),
+			vars:     make(Variables),
+		}
+	},
+}
+
+// getMatchState returns a state from the pool, the variables it
+// produces are only valid until it is released if reuse is true.
+func getMatchState(reuse bool) *matchState {
+	var st = matchPool.Get().(*matchState)
+	st.reuse = reuse
+	return st
+}
+
+// release clears the state and puts it back into the pool.
+func (st *matchState) release() {
+	if cap(st.segments) > maxPooledCaptures || cap(st.arena) > maxPooledCaptures || cap(st.values) > maxPooledCaptures {
+		return
+	}
+
+	clear(st.segments)
+	clear(st.arena)
+	clear(st.values)
+	clear(st.candidates)
+	clear(st.vars)
+	st.segments = st.segments[:0]
+	st.arena = st.arena[:0]
+	st.values = st.values[:0]
+	st.candidates = st.candidates[:0]
+	matchPool.Put(st)
+}
+
+// candidate adds a candidate for the leaf, copying the captures into the arena.
+func (st *matchState) candidate(leaf *treeLeaf, captures, rest []string, folded bool) {
+	var start = len(st.arena)
+	st.arena = append(st.arena, captures...)
+	st.candidates = append(st.candidates, treeCandidate{
+		leaf:     leaf,
+		captures: st.arena[start:len(st.arena):len(st.arena)],
+		rest:     rest,
+		folded:   folded,
+	})
+}
+
+// newVariables returns an empty map to store the variables of a candidate in.
+func (st *matchState) newVariables() Variables {
+	if !st.reuse {
+		return make(Variables)
+	}
+	clear(st.vars)
+	st.values = st.values[:0]
+	return st.vars
+}
+
+// value returns the value of a variable, in storage which is reused with the state if possible.
+func (st *matchState) value(values []string, s string) []string {
+	if !st.reuse || len(values) > 0 {
+		return append(values, s)
+	}
+	var start = len(st.values)
+	st.values = append(st.values, s)
+	return st.values[start : start+1 : start+1]
+}
+
+// keep copies the variables out of the storage of the state, so the caller can keep them.
+//
+// The values of all variables share a single slice, an empty map is returned as nil.
+func (st *matchState) keep(vars Variables) Variables {
+	if !st.reuse {
+		return vars
+	}
+	if len(vars) == 0 {
+		return nil
+	}
+
+	var n int
+	for _, v := range vars {
+		n += len(v)
+	}
+
+	var values = make([]string, 0, n)
+	var kept = make(Variables, len(vars))
+	for k, v := range vars {
+		var start = len(values)
+		values = append(values, v...)
+		kept[k] = values[start:len(values):len(values)]
+	}
+	return kept
+}
+
+// segmentsOf returns the segments of the glob, copied if they are given to the caller.
+func (st *matchState) segmentsOf(rest []string) []string {
+	if st.reuse {
+		return rest
+	}
+	return slices.Clone(rest)
+}
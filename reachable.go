@@ -0,0 +1,203 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// checkReachable reports routes in the subtree of added which can never be matched,
+// or which make an existing route unreachable. The caller holds mu.
+//
+// A route is unreachable if a route with the same pattern and method (or ANY)
+// comes before it, the earlier route always wins the tie.
+// Routes with a resolver or with matchers can fall through, and are not checked.
+//
+// The route is checked before it is added, so a panic leaves the routes unchanged.
+// Routes which are only told apart by their host or matchers have to get them before
+// they are added, I.E. through NewRoute and AddRoute.
+func (r *Mux) checkReachable(added *Route) {
+	// Children of a route which was not added yet are checked with it.
+	if added.Parent != nil && !r.attached(added.Parent) {
+		return
+	}
+	if r.reach == nil {
+		r.collectReachable(nil)
+	}
+	r.reportReachable(added)
+}
+
+// collectReachable adds the routes to the checked routes without reporting them,
+// the subtree of skip is left out.
+func (r *Mux) collectReachable(skip *Route) {
+	r.reach = make(map[string]map[string]*Route)
+	var walk func(rt *Route)
+	walk = func(rt *Route) {
+		if rt == skip {
+			return
+		}
+		r.checkRoute(rt, false)
+		for _, child := range rt.Children {
+			walk(child)
+		}
+	}
+	for _, rt := range r.routes {
+		walk(rt)
+	}
+}
+
+// reportReachable checks the route and its children, reporting the routes which are unreachable.
+//
+// The checked routes are discarded if the check panics, they are collected again on the next check.
+func (r *Mux) reportReachable(rt *Route) {
+	var done bool
+	defer func() {
+		if !done {
+			r.resetReachable()
+		}
+	}()
+
+	var walk func(rt *Route)
+	walk = func(rt *Route) {
+		r.checkRoute(rt, true)
+		for _, child := range rt.Children {
+			walk(child)
+		}
+	}
+	walk(rt)
+	done = true
+}
+
+// checkRoute adds the route to the checked routes, and reports it if it is unreachable
+// or if it makes a route which was checked before unreachable.
+func (r *Mux) checkRoute(rt *Route, report bool) {
+	if rt.Handler == nil || rt.Path == nil || rt.Path.Resolver != nil || rt.hasMatchers() {
+		return
+	}
+
+	var pattern = routePattern(rt, r.TrailingSlash == TrailingSlashStrict)
+	var methods = r.reach[pattern]
+	if methods == nil {
+		methods = make(map[string]*Route)
+		r.reach[pattern] = methods
+	}
+
+	// The route is shadowed by the first route with the same method, or with ANY.
+	for _, method := range []string{rt.Method, ANY} {
+		if shadow, ok := methods[method]; ok && r.routeBefore(shadow, rt) {
+			if report {
+				r.unreachable(rt, shadow)
+			}
+			break
+		}
+	}
+
+	// Routes added as a child of an existing route can come before routes which were checked,
+	// these are shadowed by the route if they have the same method, or if it is ANY.
+	for method, existing := range methods {
+		if (method == rt.Method || rt.Method == ANY) && r.routeBefore(rt, existing) {
+			if report {
+				r.unreachable(existing, rt)
+			}
+		}
+	}
+
+	if existing, ok := methods[rt.Method]; !ok || r.routeBefore(rt, existing) {
+		methods[rt.Method] = rt
+	}
+}
+
+// routeBefore reports whether route a comes before route b in the order routes are matched,
+// which is the order in which they were registered, with parents before their children.
+func (r *Mux) routeBefore(a, b *Route) bool {
+	var x, y = r.routeOrder(a), r.routeOrder(b)
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if x[i] != y[i] {
+			return x[i] < y[i]
+		}
+	}
+	return len(x) < len(y)
+}
+
+// routeOrder returns the index of the route among its siblings, and those of its parents.
+//
+// A route which was not added yet comes after its siblings.
+func (r *Mux) routeOrder(rt *Route) []int {
+	var order []int
+	for curr := rt; curr != nil; curr = curr.Parent {
+		var siblings = r.siblings(curr)
+		var i = slices.Index(siblings, curr)
+		if i < 0 {
+			i = len(siblings)
+		}
+		order = append(order, i)
+	}
+	slices.Reverse(order)
+	return order
+}
+
+// siblings returns the routes which the route is added to.
+func (r *Mux) siblings(rt *Route) []*Route {
+	if rt.Parent != nil {
+		return rt.Parent.Children
+	}
+	return r.routes
+}
+
+// attached reports whether the route and its parents are part of the routes of the mux.
+func (r *Mux) attached(rt *Route) bool {
+	for curr := rt; curr != nil; curr = curr.Parent {
+		if !slices.Contains(r.siblings(curr), curr) {
+			return false
+		}
+	}
+	return true
+}
+
+// resetReachable discards the checked routes, they are collected again on the next check.
+// The caller holds mu.
+func (r *Mux) resetReachable() {
+	r.reach = nil
+}
+
+// routeChanged checks the route and its children again after its host pattern or matchers changed.
+// Routes which were not added yet are checked when they are added. The caller holds mu.
+func (r *Mux) routeChanged(rt *Route) {
+	if !r.attached(rt) {
+		return
+	}
+	r.collectReachable(rt)
+	r.reportReachable(rt)
+}
+
+func (r *Mux) unreachable(route, shadowedBy *Route) {
+	var msg = fmt.Sprintf(
+		"mux: route %s %q can never be matched, it is shadowed by %s %q",
+		route.Method, route.Path.String(), shadowedBy.Method, shadowedBy.Path.String(),
+	)
+	if r.StrictRoutes {
+		panic(msg)
+	}
+	log.Print(msg)
+}
+
+// routePattern returns the full pattern of the route, without the names of its variables.
+//
+// If slash is true, the trailing slash of the path is part of the pattern.
+func routePattern(rt *Route, slash bool) string {
+	var b strings.Builder
+	b.WriteString(rt.HostPattern().signature())
+	for _, part := range pathParts(rt.Path) {
+		b.WriteString(URL_DELIM)
+		b.WriteString(part.signature())
+	}
+	if slash && rt.Path.trailingSlash() {
+		b.WriteString(URL_DELIM)
+	}
+	b.WriteString("\x00")
+	return b.String()
+}
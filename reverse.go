@@ -0,0 +1,140 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ReverseOption changes the URL built by ReverseMap and AbsoluteURL.
+type ReverseOption func(u *url.URL)
+
+// WithQuery adds the query parameters to the URL.
+func WithQuery(query url.Values) ReverseOption {
+	return func(u *url.URL) {
+		var q = u.Query()
+		for key, values := range query {
+			for _, value := range values {
+				q.Add(key, value)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+}
+
+// WithFragment sets the fragment of the URL.
+func WithFragment(fragment string) ReverseOption {
+	return func(u *url.URL) {
+		u.Fragment = fragment
+	}
+}
+
+// ReverseMap returns the URL of the route with the variables replaced by name.
+//
+// The URL includes the host if the route has a host pattern, like ReverseURL.
+// Optional variables can be left out, the value of a glob can be a []string.
+//
+// It returns an error wrapping ErrUnknownVariable or ErrMissingVariable
+// if a variable is not part of the route, or if a variable of the route was not provided.
+func (r *Mux) ReverseMap(name string, variables map[string]any, opts ...ReverseOption) (string, error) {
+	var u, err = r.reverseMap(name, variables, opts)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// AbsoluteURL returns the absolute URL of the route with the variables replaced by name, see ReverseMap.
+//
+// The scheme is taken from the request, the host is taken from the request unless the route
+// has a host pattern. If TrustProxyHeaders is set, the forwarding headers of the request
+// are taken into account, see GetForwardedScheme and GetForwardedHost.
+func (r *Mux) AbsoluteURL(req *http.Request, name string, variables map[string]any, opts ...ReverseOption) (*url.URL, error) {
+	var u, err = r.reverseMap(name, variables, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Scheme = GetForwardedScheme(req, r.TrustProxyHeaders)
+	if u.Host == "" {
+		u.Host = GetForwardedHost(req, r.TrustProxyHeaders)
+	}
+	return u, nil
+}
+
+func (r *Mux) reverseMap(name string, variables map[string]any, opts []ReverseOption) (*url.URL, error) {
+	var u, err = r.reverseNamed(name, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u, nil
+}
+
+func (r *Mux) reverseNamed(name string, variables map[string]any) (*url.URL, error) {
+	var route = r.nameIndex()[name]
+	if route == nil {
+		var mount, m, rest = r.findMount(name)
+		if mount == nil {
+			return nil, ErrRouteNotFound
+		}
+		return r.reverseMount(mount, m, rest, variables)
+	}
+
+	var values, err = route.orderVariables(variables)
+	if err != nil {
+		return nil, err
+	}
+	return route.reverseURL(values...)
+}
+
+// orderVariables returns the values of the variables in the order they appear in
+// the host pattern and path of the route, as expected by ReverseURL.
+func (r *Route) orderVariables(variables map[string]any) ([]interface{}, error) {
+	var parts = pathParts(r.Path)
+	if host := r.HostPattern(); host != nil {
+		parts = append(host.Parts[:len(host.Parts):len(host.Parts)], parts...)
+	}
+
+	var (
+		values  = make([]interface{}, 0, len(variables))
+		known   = make(map[string]struct{}, len(variables))
+		omitted []*PathPart
+	)
+	for i, part := range parts {
+		for _, name := range part.Names() {
+			known[name] = struct{}{}
+
+			var value, ok = variables[name]
+			switch {
+			case ok:
+				// Optional parts before this one were left out, fill in their defaults.
+				for _, o := range omitted {
+					if o.Default == "" {
+						return nil, fmt.Errorf("%w: %q", ErrMissingVariable, o.Part)
+					}
+					values = append(values, o.Default)
+				}
+				omitted = nil
+				values = append(values, value)
+			case part.Optional:
+				omitted = append(omitted, part)
+			case part.IsGlob && i == len(parts)-1:
+				values = append(values, []string{})
+			default:
+				return nil, fmt.Errorf("%w: %q", ErrMissingVariable, name)
+			}
+		}
+	}
+
+	for name := range variables {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownVariable, name)
+		}
+	}
+
+	return values, nil
+}
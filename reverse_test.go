@@ -0,0 +1,90 @@
+package mux_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func newReverseMux() *mux.Mux {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	m.Handle(mux.GET, "/users/<<id:int>>/posts/<<slug>>/", h, "post")
+	m.Handle(mux.GET, "/archive/<<year?:int>>/<<month?1:int>>/<<day?:int>>/", h, "archive")
+	m.Handle(mux.GET, "/files/<<path...>>", h, "files")
+	m.Host("<<tenant>>.example.com", "tenant").Handle(mux.GET, "/about/", h, "about")
+	return m
+}
+
+func TestReverseMap(t *testing.T) {
+	var m = newReverseMux()
+
+	var tests = []struct {
+		name      string
+		variables map[string]any
+		opts      []mux.ReverseOption
+		expected  string
+		err       error
+	}{
+		{"post", map[string]any{"slug": "hello", "id": 1}, nil, "/users/1/posts/hello/", nil},
+		{"post", map[string]any{"id": 1, "slug": "a b"}, []mux.ReverseOption{
+			mux.WithQuery(url.Values{"page": {"2"}, "q": {"x&y"}}),
+			mux.WithFragment("comments"),
+		}, "/users/1/posts/a%20b/?page=2&q=x%26y#comments", nil},
+		{"archive", map[string]any{}, nil, "/archive/", nil},
+		{"archive", map[string]any{"year": 2024}, nil, "/archive/2024/", nil},
+		{"archive", map[string]any{"year": 2024, "day": 3}, nil, "/archive/2024/1/3/", nil},
+		{"files", map[string]any{"path": []string{"a", "b.txt"}}, nil, "/files/a/b.txt", nil},
+		{"files", map[string]any{}, nil, "/files/", nil},
+		{"tenant:about", map[string]any{"tenant": "acme"}, nil, "//acme.example.com/about/", nil},
+		{"post", map[string]any{"id": 1}, nil, "", mux.ErrMissingVariable},
+		{"post", map[string]any{"id": 1, "slug": "a", "page": 2}, nil, "", mux.ErrUnknownVariable},
+		{"archive", map[string]any{"day": 3}, nil, "", mux.ErrMissingVariable},
+		{"post", map[string]any{"id": "x", "slug": "a"}, nil, "", mux.ErrInvalidVariable},
+		{"missing", nil, nil, "", mux.ErrRouteNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var path, err = m.ReverseMap(test.name, test.variables, test.opts...)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Expected error %v, got %v", test.err, err)
+			}
+			if path != test.expected {
+				t.Fatalf("Expected %q, got %q", test.expected, path)
+			}
+		})
+	}
+}
+
+func TestAbsoluteURL(t *testing.T) {
+	var m = newReverseMux()
+	var vars = map[string]any{"id": 1, "slug": "hello"}
+
+	var req = httptest.NewRequest(mux.GET, "http://example.com:8080/", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "public.example.com")
+
+	var u, err = m.AbsoluteURL(req, "post", vars, mux.WithQuery(url.Values{"a": {"b"}}))
+	if err != nil || u.String() != "http://example.com:8080/users/1/posts/hello/?a=b" {
+		t.Fatalf("Expected the forwarding headers to be ignored, got %v (%v)", u, err)
+	}
+
+	m.TrustProxyHeaders = true
+	if u, _ = m.AbsoluteURL(req, "post", vars); u.String() != "https://public.example.com/users/1/posts/hello/" {
+		t.Fatalf("Expected the X-Forwarded headers to be used, got %v", u)
+	}
+
+	req.Header.Set("Forwarded", `for=192.0.2.60;proto=https;host="forwarded.example.com", for=198.51.100.17`)
+	if u, _ = m.AbsoluteURL(req, "post", vars); u.String() != "https://forwarded.example.com/users/1/posts/hello/" {
+		t.Fatalf("Expected the Forwarded header to be used, got %v", u)
+	}
+
+	if u, _ = m.AbsoluteURL(req, "tenant:about", map[string]any{"tenant": "acme"}); u.String() != "https://acme.example.com/about/" {
+		t.Fatalf("Expected the host of the route to be used, got %v", u)
+	}
+}
@@ -9,19 +9,34 @@ import (
 	"strings"
 )
 
+// Route is a handler registered for a path, and the parent of the routes added to it.
+//
+// The handler and middleware of a route are composed into a chain when the routes are compiled, see Mux.Compile.
+// Once the route was added, change them through Use, Preprocess and RunsMiddleware,
+// the Handler, Middleware and PreMiddleware fields must not be changed directly.
 type Route struct {
 	Name               string
 	Method             string
 	Middleware         []Middleware
 	PreMiddleware      []Middleware
 	Path               *PathInfo
+	Host               *HostInfo        // Optional host pattern, applies to the children of the route as well.
+	Matchers           []RequestMatcher // Optional request matchers, apply to the children of the route as well.
 	Children           []*Route
 	Handler            Handler
 	Parent             *Route
 	ParentMux          *Mux
 	DisabledMiddleware bool // Is middleware disabled for this route?
+	CaseInsensitive    bool // Match static text case-insensitively, applies to the children of the route as well.
+
+	// The names of the groups the route was added in, which come before its name in PathName, see Mux.Group.
+	NamePrefix []string
 
 	identifier int64
+
+	// Whether the route was added by the update which is running,
+	// which holds the lock of the mux. See Mux.Update.
+	staged bool
 }
 
 func newRoute(method string, handler Handler, name ...string) *Route {
@@ -50,17 +65,30 @@ func (r *Route) ID() int64 {
 
 // Use adds middleware to the route.
 func (r *Route) Use(middleware ...Middleware) {
+	defer r.lock()()
 	r.Middleware = append(r.Middleware, middleware...)
+	r.middlewareChanged()
 }
 
 // Preprocess adds middleware to the route that will be executed before any other middleware or handler.
 func (r *Route) Preprocess(middleware ...Middleware) {
+	defer r.lock()()
 	r.PreMiddleware = append(r.PreMiddleware, middleware...)
+	r.middlewareChanged()
 }
 
 // DisableMiddleware disables the middleware for the route.
 func (r *Route) RunsMiddleware(b bool) {
+	defer r.lock()()
 	r.DisabledMiddleware = !b
+	r.middlewareChanged()
+}
+
+// middlewareChanged discards the compiled middleware chain of the route.
+func (r *Route) middlewareChanged() {
+	if r.ParentMux != nil {
+		r.ParentMux.invalidate()
+	}
 }
 
 // String returns a string representation of the route.
@@ -73,6 +101,9 @@ func (r *Route) PathName() []string {
 	var parts []string = make([]string, 0)
 	for curr != nil {
 		parts = append(parts, curr.Name)
+		for i := len(curr.NamePrefix) - 1; i >= 0; i-- {
+			parts = append(parts, curr.NamePrefix[i])
+		}
 		curr = curr.Parent
 	}
 	slices.Reverse(parts)
@@ -84,6 +115,13 @@ func (r *Route) Find(names []string) (*Route, bool) {
 }
 
 func (r *Route) find(names []string, index int) (*Route, bool) {
+	// The names of the groups come before the name of the route.
+	for _, prefix := range r.NamePrefix {
+		if len(names) <= index || names[index] != prefix {
+			return nil, false
+		}
+		index++
+	}
 	if len(names) <= index {
 		return nil, false
 	}
@@ -105,18 +143,34 @@ func (r *Route) find(names []string, index int) (*Route, bool) {
 	return nil, false
 }
 
+// lock locks the route table of the mux the route belongs to, and returns the function to unlock it.
+//
+// Routes added by a running update are changed by the goroutine which holds the lock, they are not locked again.
+func (r *Route) lock() (unlock func()) {
+	if r.ParentMux == nil || r.staged {
+		return func() {}
+	}
+	r.ParentMux.mu.Lock()
+	return r.ParentMux.mu.Unlock
+}
+
 func (r *Route) RemoveByPath(path string) bool {
+	defer r.lock()()
+	return r.removeByPath(path)
+}
+
+func (r *Route) removeByPath(path string) bool {
 	path = strings.Trim(path, "/")
 	var routePath = strings.Trim(r.Path.String(), "/")
 	if path == routePath && r.Parent != nil {
-		r.Parent.RemoveChild(r)
+		r.Parent.removeChild(r)
 		return true
 	} else if path == routePath && r.Parent == nil {
-		r.ParentMux.RemoveRoute(r)
+		r.ParentMux.removeRoute(r)
 		return true
 	}
 	for _, child := range r.Children {
-		if child.RemoveByPath(path) {
+		if child.removeByPath(path) {
 			return true
 		}
 	}
@@ -124,7 +178,20 @@ func (r *Route) RemoveByPath(path string) bool {
 }
 
 func (r *Route) RemoveChild(child *Route) {
-	removeRoute(r.Children, child)
+	defer r.lock()()
+	r.removeChild(child)
+}
+
+func (r *Route) removeChild(child *Route) {
+	if r.ParentMux != nil && r.ParentMux.tx != nil {
+		r.ParentMux.tx.save(r)
+	}
+	r.Children = removeRoute(r.Children, child)
+	if r.ParentMux != nil {
+		r.ParentMux.resetNames()
+		r.ParentMux.resetReachable()
+		r.ParentMux.invalidate()
+	}
 }
 
 // Helper function to check if the route matches the method and path.
@@ -160,11 +227,12 @@ func (r *Route) Match(method string, path []string) (*Route, bool, Variables) {
 // matchFrom continues matching a child route starting at `matchFrom`.
 // `inherited` carries variables already captured by ancestors.
 func (r *Route) matchFrom(method string, path []string, matchFrom int, vars Variables) (*Route, bool, Variables) {
-	if len(vars) > 0 {
+	// Start with a shallow copy of inherited vars so siblings don't mutate each other,
+	// this is only needed if the path of the route stores variables.
+	if len(vars) > 0 && r.Path.storesVariables() {
 		vars = maps.Clone(vars)
 	}
 
-	// Start with a shallow copy of inherited vars so siblings don't mutate each other.
 	matchedHere, nextFrom, vars := r.Path.Match(path, matchFrom, vars)
 	if nextFrom == -1 {
 		return nil, false, nil
@@ -190,16 +258,15 @@ func randInt64() int64 {
 	return n.Int64()
 }
 
+// removeRoute returns the routes without match.
+//
+// The slice is copied, not modified, as it may still be read through a snapshot of the routes.
 func removeRoute(s []*Route, match *Route) []*Route {
 	for i, r := range s {
 		if r.identifier == match.identifier {
-			if len(s) == 1 {
-				return make([]*Route, 0)
-			} else if i == len(s)-1 {
-				return s[:i]
-			} else {
-				return append(s[:i], s[i+1:]...)
-			}
+			var routes = make([]*Route, 0, len(s)-1)
+			routes = append(routes, s[:i]...)
+			return append(routes, s[i+1:]...)
 		}
 	}
 	return s
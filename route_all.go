@@ -58,21 +58,77 @@ func setChildData(child, parent *Route) {
 	}
 }
 
+// parsePaths parses the path and host of the route and its children again with the syntax of the mux,
+// if they were parsed with another syntax, I.E. by NewRoute before the route was added to the mux.
+func (r *Mux) parsePaths(rt *Route) {
+	var syntax = r.pathSyntax()
+	if rt.Path != nil && !parsedWith(rt.Path.Path, syntax) {
+		var path = NewPathInfo(rt, (&PathInfo{Path: rt.Path.Path}).String())
+		path.Parent = rt.Path.Parent
+		path.TrailingSlash = rt.Path.TrailingSlash
+		if rt.Path.Resolver != nil {
+			path.Resolver = rt.Path.Resolver
+		}
+		rt.Path = path
+		rebase(rt)
+	}
+	if rt.Host != nil && !parsedWith(rt.Host.Parts, syntax) {
+		rt.Host = NewHostInfo(rt, rt.Host.String())
+	}
+	for _, child := range rt.Children {
+		r.parsePaths(child)
+	}
+}
+
+// parsedWith reports whether the parts were parsed with the syntax.
+func parsedWith(parts []*PathPart, syntax *PathSyntax) bool {
+	for _, part := range parts {
+		if *part.pathSyntax() != *syntax {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *Route) AddRoute(rt *Route) {
+	defer r.lock()()
+	r.addRoute(rt)
+}
+
+func (r *Route) addRoute(rt *Route) {
 	setChildData(rt, r)
 
 	for _, child := range rt.Children {
 		setChildData(child, rt)
 	}
 
+	if r.ParentMux != nil {
+		r.ParentMux.parsePaths(rt)
+		r.ParentMux.bindConverters(rt)
+		r.ParentMux.checkReachable(rt)
+	}
+
+	if r.ParentMux != nil && r.ParentMux.tx != nil {
+		r.ParentMux.tx.save(r)
+		if r.staged {
+			r.ParentMux.tx.stage(rt)
+		}
+	}
+
 	r.Children = append(r.Children, rt)
+	if r.ParentMux != nil {
+		r.ParentMux.indexNames(rt)
+		r.ParentMux.invalidate()
+	}
 }
 
 // Handle adds a handler to the route.
 //
 // It returns the route that was added so that it can be used to add children.
 func (r *Route) Handle(method string, path string, handler Handler, name ...string) *Route {
-	var route = NewRoute(method, path, handler, name...)
+	var route = newRoute(method, handler, name...)
+	route.ParentMux = r.ParentMux
+	route.Path = NewPathInfo(route, path)
 	r.AddRoute(route)
 	return route
 }
@@ -1,7 +1,7 @@
 package mux
 
 import (
-	"strings"
+	"net/url"
 )
 
 const (
@@ -25,39 +25,113 @@ func New() *Mux {
 	return &Mux{}
 }
 
+// Use adds middleware which runs for all routes, after the pre-middleware of the route
+// and before its own middleware. The middleware chains of the routes are composed again.
 func (r *Mux) Use(middleware ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.middleware = append(r.middleware, middleware...)
+	r.invalidate()
 }
 
 func (r *Mux) RemoveByPath(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removeByPath(path)
+}
+
+func (r *Mux) removeByPath(path string) {
 	for _, route := range r.routes {
-		route.RemoveByPath(path)
+		route.removeByPath(path)
 	}
 }
 
 func (r *Mux) RemoveRoute(route *Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removeRoute(route)
+}
+
+func (r *Mux) removeRoute(route *Route) {
 	r.routes = removeRoute(r.routes, route)
+	r.resetNames()
+	r.resetReachable()
+	r.invalidate()
 }
 
 func (r *Mux) ResetRoutes() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resetRoutes()
+}
+
+func (r *Mux) resetRoutes() {
 	r.routes = make([]*Route, 0)
+	r.resetNames()
+	r.resetReachable()
+	r.invalidate()
 }
 
+// Find returns the route with the fully qualified name, see Route.FullName.
+//
+// Routes are looked up in an index which is kept up to date when routes are added or removed.
+// The routes of a mounted mux are found by their name prefixed with the name of the mount,
+// their paths are relative to the mount, see Mux.Mount.
 func (r *Mux) Find(name string) *Route {
-	var nameparts = strings.Split(name, NAME_SEPARATOR)
-	for _, route := range r.routes {
-		var route, ok = route.Find(nameparts)
-		if ok {
-			return route
-		}
+	if route := r.nameIndex()[name]; route != nil {
+		return route
+	}
+	if _, m, rest := r.findMount(name); m != nil {
+		return m.mux.Find(rest)
 	}
 	return nil
 }
 
 func (r *Mux) Reverse(name string, variables ...interface{}) (string, error) {
-	var route = r.Find(name)
+	var route = r.nameIndex()[name]
 	if route == nil {
-		return "", ErrRouteNotFound
+		var mount, _, rest = r.findMount(name)
+		if mount == nil {
+			return "", ErrRouteNotFound
+		}
+		return mount.Path.Reverse(mountVariables(mount, false, rest, variables)...)
 	}
 	return route.Path.Reverse(variables...)
 }
+
+// ReverseURL returns the URL of the route, including the host if the route has a host pattern.
+//
+// The variables of the host come first, followed by the variables of the path.
+// The scheme of the URL is left empty.
+func (r *Mux) ReverseURL(name string, variables ...interface{}) (*url.URL, error) {
+	var route = r.nameIndex()[name]
+	if route == nil {
+		var mount, _, rest = r.findMount(name)
+		if mount == nil {
+			return nil, ErrRouteNotFound
+		}
+		return mount.reverseURL(mountVariables(mount, true, rest, variables)...)
+	}
+	return route.reverseURL(variables...)
+}
+
+// reverseURL returns the URL of the route, the variables of the host come first.
+func (r *Route) reverseURL(variables ...interface{}) (*url.URL, error) {
+	var u = &url.URL{}
+	if host := r.HostPattern(); host != nil {
+		var h, n, err = host.Reverse(variables...)
+		if err != nil {
+			return nil, err
+		}
+		u.Host = h
+		variables = variables[n:]
+	}
+
+	var path, err = r.Path.Reverse(variables...)
+	if err != nil {
+		return nil, err
+	}
+	u.Path, _ = url.PathUnescape(path)
+	u.RawPath = path
+	return u, nil
+}
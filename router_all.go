@@ -3,7 +3,13 @@
 
 package mux
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
 
 var (
 	_ Multiplexer = (*Mux)(nil)
@@ -32,6 +38,87 @@ type Mux struct {
 	routes          []*Route
 	middleware      []Middleware
 	NotFoundHandler http.HandlerFunc
+
+	// MethodNotAllowedHandler is called when the path matched a route,
+	// but the method of the request is not allowed by any of the matched routes.
+	//
+	// The Allow header is set on the response before the handler is called,
+	// the response runs through the middleware of the mux.
+	MethodNotAllowedHandler http.HandlerFunc
+
+	// AutoOptions answers OPTIONS requests with the methods registered for the path,
+	// unless an OPTIONS route was registered explicitly.
+	//
+	// The automatic response runs through the middleware of the mux, I.E. to handle CORS preflight requests.
+	AutoOptions bool
+
+	// AutoHead serves HEAD requests with the GET route of the path, discarding the body,
+	// unless a HEAD route was registered explicitly.
+	AutoHead bool
+
+	// StrictRoutes panics when a route is registered which can never be matched,
+	// because a route with the same pattern and method was registered before it.
+	// The route is not added if it panics.
+	//
+	// Routes are checked as they are registered, routes which are only told apart
+	// by their host or matchers have to get them before they are added, see NewRoute.
+	//
+	// If false, a warning is logged instead.
+	StrictRoutes bool
+
+	// TrailingSlash decides how the trailing slash of a request path is treated.
+	TrailingSlash TrailingSlashPolicy
+
+	// CleanPath redirects requests for paths containing empty, `.` or `..` segments
+	// to the cleaned path, see CleanPath.
+	CleanPath bool
+
+	// IgnoreCase matches static text in paths case-insensitively for all routes.
+	// Variables keep the case of the request path.
+	//
+	// Use Route.IgnoreCase to do this for a subtree only.
+	IgnoreCase bool
+
+	// CaseRedirect redirects requests which only matched a route when ignoring case
+	// to the canonical lowercase path of the route, instead of serving them.
+	// The values of variables keep the case of the request.
+	CaseRedirect bool
+
+	// Syntax is the syntax used to parse the paths of routes added to the mux.
+	//
+	// It has to be set before any routes are added, if nil the DefaultPathSyntax is used.
+	// Routes which were parsed with another syntax, I.E. by NewRoute, are parsed again when they are added.
+	Syntax *PathSyntax
+
+	// UniqueNames panics when a route is registered with a fully qualified name
+	// which is already in use. If false, the route which was registered first keeps the name.
+	UniqueNames bool
+
+	// TrustProxyHeaders takes the forwarding headers of requests into account when
+	// building absolute URLs, only set it when running behind a trusted proxy.
+	TrustProxyHeaders bool
+
+	// Converters registered with RegisterConverter.
+	converters map[string]Converter
+
+	// The routes which were checked for reachability by their pattern and method.
+	reach map[string]map[string]*Route
+
+	// The routes by their fully qualified name, see Route.FullName.
+	//
+	// The published index is a snapshot which is never modified, nameIdx
+	// is kept up to date while changing the routes and is only used with mu held.
+	names   atomic.Pointer[map[string]*Route]
+	nameIdx map[string]*Route
+
+	// The compiled route tree, built lazily on the first match
+	// after the route table has changed.
+	tree atomic.Pointer[routeTree]
+
+	// mu guards changes to the route table and building the snapshots of it,
+	// tx is the update which is running, see Mux.Update.
+	mu sync.Mutex
+	tx *Tx
 }
 
 // Namespace allows you to create a new Multiplexer with speficic
@@ -41,39 +128,38 @@ func (r *Mux) Namespace(opts NamespaceOptions) Multiplexer {
 }
 
 func (r *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	var route, variables = r.Match(req.Method, req.URL.Path)
-	if route == nil || route.Handler == nil {
-		r.NotFound(w, req)
+	// The state is reused once the request was served,
+	// the variables are copied out of it before they are stored in the context.
+	var st = getMatchState(true)
+	defer st.release()
+
+	var leaf, variables, head, fallback = r.matchRequest(st, req.Method, GetHost(req), req.URL.EscapedPath(), req)
+	if fallback != nil {
+		fallback(w, req)
 		return
 	}
 
-	req = SetContextVars(req, variables)
-	req = req.WithContext(ContextWithRoute(
-		req.Context(), route,
-	))
-
-	var handler Handler = route.Handler
-	if bindable, ok := route.Handler.(BindableHandler); ok {
-		handler = bindable.Bind(req, route, variables)
+	if leaf == nil {
+		r.NotFound(w, req)
+		return
 	}
 
-	// Do not run middleware if disabled.
-	//lint:ignore S1002 Extra verbose to make it more clear.
-	if route.DisabledMiddleware == false {
-		for i := len(route.Middleware) - 1; i >= 0; i-- {
-			handler = route.Middleware[i](handler)
-		}
-
-		for i := len(r.middleware) - 1; i >= 0; i-- {
-			handler = r.middleware[i](handler)
-		}
+	if head {
+		var hw = newHeadResponseWriter(w)
+		defer hw.finish()
+		w = hw
+	}
 
-		for i := len(route.PreMiddleware) - 1; i >= 0; i-- {
-			handler = route.PreMiddleware[i](handler)
-		}
+	// The record is allocated for the request, as contexts may outlive it.
+	var record = &matchRecord{
+		route:   leaf.route,
+		pattern: leaf.pattern,
+		vars:    st.keep(variables),
 	}
+	req = req.WithContext(context.WithValue(req.Context(), matchContextKey, record))
 
-	handler.ServeHTTP(w, req)
+	// The handler is already wrapped in the middleware of the route and the mux.
+	leaf.handler.ServeHTTP(w, req)
 }
 
 // Mirrors ServeHTTP but returns the handler, variables, and whether a match was found.
@@ -81,42 +167,35 @@ func (r *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 // ServeHTTP is more efficient if you just want to serve the request directly,
 // skipping an extra allocation for the returned function (handler).
 func (r *Mux) Resolve(method, path string) (http.Handler, Variables, bool) {
-	var route, variables = r.Match(method, path)
-	if route == nil || route.Handler == nil {
-		return http.HandlerFunc(r.NotFound), nil, false
-	}
-
-	var handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		req = SetContextVars(req, variables)
-		req = req.WithContext(ContextWithRoute(
-			req.Context(), route,
-		))
+	var st = getMatchState(false)
+	defer st.release()
 
-		var h Handler = route.Handler
-		if bindable, ok := h.(BindableHandler); ok {
-			h = bindable.Bind(req, route, variables)
-		}
+	var leaf, variables, head, fallback = r.matchRequest(st, method, "", path, nil)
+	if fallback != nil {
+		return fallback, nil, false
+	}
 
-		// Do not run middleware if disabled.
-		//lint:ignore S1002 Extra verbose to make it more clear.
-		if route.DisabledMiddleware == false {
-			for i := len(route.Middleware) - 1; i >= 0; i-- {
-				h = route.Middleware[i](h)
-			}
+	if leaf == nil {
+		return http.HandlerFunc(r.NotFound), nil, false
+	}
 
-			for i := len(r.middleware) - 1; i >= 0; i-- {
-				h = r.middleware[i](h)
-			}
+	var record = &matchRecord{
+		route:   leaf.route,
+		pattern: leaf.pattern,
+		vars:    variables,
+	}
+	var handler = leaf.handler
 
-			for i := len(route.PreMiddleware) - 1; i >= 0; i-- {
-				h = route.PreMiddleware[i](h)
-			}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if head {
+			var hw = newHeadResponseWriter(w)
+			defer hw.finish()
+			w = hw
 		}
 
-		h.ServeHTTP(w, req)
-	})
-
-	return handler, variables, true
+		req = req.WithContext(context.WithValue(req.Context(), matchContextKey, record))
+		handler.ServeHTTP(w, req)
+	}), variables, true
 }
 
 func (r *Mux) NotFound(w http.ResponseWriter, req *http.Request) {
@@ -127,36 +206,111 @@ func (r *Mux) NotFound(w http.ResponseWriter, req *http.Request) {
 	http.NotFound(w, req)
 }
 
+// MethodNotAllowed sets the Allow header and responds with a 405 status,
+// or calls the MethodNotAllowedHandler if it was set.
+func (r *Mux) MethodNotAllowed(w http.ResponseWriter, req *http.Request, allowed []string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	if r.MethodNotAllowedHandler != nil {
+		r.MethodNotAllowedHandler(w, req)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// Match returns the route which matches the method and path, along with the variables in the path.
+//
+// Host patterns and matchers of routes are not checked, use MatchHost to match routes by host,
+// or MatchRequest to match routes against all properties of a request.
+//
+// If multiple routes match, the most specific route is returned:
+// static segments win over variables, which win over globs,
+// regardless of the order in which the routes were registered.
+//
+// The path is expected in its escaped form, as returned by Reverse.
+// Segments are unescaped after splitting, so an escaped slash stays inside its segment.
 func (r *Mux) Match(method string, path string) (*Route, Variables) {
-	var parts = SplitPath(path)
-	var vars Variables
-	for _, route := range r.routes {
-		if len(vars) > 0 {
-			vars = nil
-		}
+	var st = getMatchState(false)
+	defer st.release()
+	var route, vars, _ = r.compiled().match(st, r.newMatchInput(st, method, "", path, nil))
+	return route, vars
+}
 
-		ok, from, vars := route.Path.Match(parts, 0, vars)
-		if from == -1 {
-			continue
-		}
+// MatchHost returns the route which matches the method, host and path,
+// along with the variables in the host and the path.
+func (r *Mux) MatchHost(method, host, path string) (*Route, Variables) {
+	var st = getMatchState(false)
+	defer st.release()
+	var route, vars, _ = r.compiled().match(st, r.newMatchInput(st, method, host, path, nil))
+	return route, vars
+}
 
-		// Full match at this level AND method matches -> return this route.
-		if routeMatched(ok, method, route) {
-			return route, vars
-		}
+// MatchRequest returns the route which matches the request, along with the variables in the host and the path.
+//
+// The host patterns and matchers of routes are checked against the request.
+func (r *Mux) MatchRequest(req *http.Request) (*Route, Variables) {
+	var st = getMatchState(false)
+	defer st.release()
+	var route, vars, _ = r.compiled().match(st, r.newMatchInput(st, req.Method, GetHost(req), req.URL.EscapedPath(), req))
+	return route, vars
+}
 
-		// Partial: explore children from `from`.
-		for _, child := range route.Children {
-			if rt, matched, v := child.matchFrom(method, parts, from, vars); routeMatched(matched, method, rt) {
-				return rt, v
-			}
-		}
+// Host returns a route without a handler which restricts
+// the routes added to it to hosts matching the pattern.
+//
+// The pattern can contain variables, I.E. `<<tenant>>.example.com`.
+func (r *Mux) Host(pattern string, name ...string) *Route {
+	return r.Handle(ANY, "/", nil, name...).WithHost(pattern)
+}
+
+// compiled returns the route tree, building it if the route table has changed.
+//
+// The tree is never modified, requests which already loaded it
+// keep using it while the routes are changed.
+func (r *Mux) compiled() *routeTree {
+	if t := r.tree.Load(); t != nil {
+		return t
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.compile()
+}
+
+// compile builds and publishes the route tree if needed, the caller holds mu.
+func (r *Mux) compile() *routeTree {
+	if t := r.tree.Load(); t != nil {
+		return t
 	}
-	return nil, nil
+
+	var t = buildTree(r.routes, r.middleware)
+	r.tree.Store(t)
+	return t
+}
+
+// invalidate discards the published route tree and name index, the caller holds mu.
+//
+// During an update this is left to the commit, so lookups
+// keep using the snapshots from before the update.
+func (r *Mux) invalidate() {
+	if r.tx != nil {
+		return
+	}
+	r.tree.Store(nil)
+	r.names.Store(nil)
 }
 
 func (r *Mux) Handle(method string, path string, handler Handler, name ...string) *Route {
-	var route = NewRoute(method, path, handler, name...)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.handle(method, path, handler, name...)
+}
+
+func (r *Mux) handle(method string, path string, handler Handler, name ...string) *Route {
+	var route = newRoute(method, handler, name...)
+	route.ParentMux = r
+	route.Path = NewPathInfo(route, path)
+	r.bindConverters(route)
+	r.checkReachable(route)
 	r.routes = append(r.routes, route)
 
 	setChildData(route, nil)
@@ -165,10 +319,18 @@ func (r *Mux) Handle(method string, path string, handler Handler, name ...string
 		setChildData(child, route)
 	}
 
+	r.indexNames(route)
+	r.invalidate()
 	return route
 }
 
 func (r *Mux) AddRoute(rt *Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addRoute(rt)
+}
+
+func (r *Mux) addRoute(rt *Route) {
 	rt.ParentMux = r
 
 	if rt.identifier == 0 {
@@ -179,7 +341,12 @@ func (r *Mux) AddRoute(rt *Route) {
 		setChildData(child, rt)
 	}
 
+	r.parsePaths(rt)
+	r.bindConverters(rt)
+	r.checkReachable(rt)
 	r.routes = append(r.routes, rt)
+	r.indexNames(rt)
+	r.invalidate()
 }
 
 func (r *Mux) HandleFunc(method string, path string, handler func(w http.ResponseWriter, r *http.Request), name ...string) *Route {
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall/js"
 )
 
@@ -79,6 +81,31 @@ type Mux struct {
 	middleware      []Middleware
 	NotFoundHandler Handler
 
+	// Syntax is the syntax used to parse the paths of routes added to the mux.
+	Syntax *PathSyntax
+
+	// TrustProxyHeaders takes the forwarding headers of requests into account when
+	// building absolute URLs, only set it when running behind a trusted proxy.
+	TrustProxyHeaders bool
+
+	// Converters registered with RegisterConverter.
+	converters map[string]Converter
+
+	// The routes by their fully qualified name, see Route.FullName.
+	//
+	// The published index is a snapshot which is never modified, nameIdx
+	// is kept up to date while changing the routes and is only used with mu held.
+	names   atomic.Pointer[map[string]*Route]
+	nameIdx map[string]*Route
+
+	// mu guards changes to the route table, tx is always nil in the browser.
+	mu sync.Mutex
+	tx *Tx
+
+	// UniqueNames panics when a route is registered with a fully qualified name
+	// which is already in use.
+	UniqueNames bool
+
 	running              bool
 	routerChangePageFunc js.Func
 	jsChangePageFunc     js.Func
@@ -87,6 +114,9 @@ type Mux struct {
 	alwaysInvokeRoute    bool
 }
 
+// invalidate is a no-op, routes are matched directly in the browser.
+func (r *Mux) invalidate() {}
+
 func (r *Mux) FirstPage(path string) {
 	r.firstPageURL = path
 }
@@ -315,3 +345,31 @@ func (r *Mux) Handle(path string, handler Handler, name ...string) *Route {
 func pushState(state js.Value, path string) {
 	global.Get("history").Call("pushState", state, nil, path)
 }
+
+// resetReachable is a no-op, routes are not checked for reachability in the browser.
+func (r *Mux) resetReachable() {}
+
+// routeChanged is a no-op, routes are not checked for reachability in the browser.
+func (r *Mux) routeChanged(rt *Route) {}
+
+// Tx is not supported in the browser, routes are changed directly.
+type Tx struct{}
+
+func (tx *Tx) save(rt *Route) {}
+
+// mountedMux is not supported in the browser, handlers cannot be mounted.
+type mountedMux struct {
+	mux *Mux
+}
+
+func (r *Mux) findMount(name string) (*Route, *mountedMux, string) {
+	return nil, nil, ""
+}
+
+func mountVariables(rt *Route, host bool, name string, variables []interface{}) []interface{} {
+	return variables
+}
+
+func (r *Mux) reverseMount(rt *Route, m *mountedMux, name string, variables map[string]any) (*url.URL, error) {
+	return nil, ErrRouteNotFound
+}
@@ -40,7 +40,7 @@ func (ns *nameSpace[T]) routeSetup(r *Route) {
 	}
 
 	if ns.onRouteServe != nil {
-		r.Middleware = append(r.Middleware, func(next Handler) Handler {
+		r.Use(func(next Handler) Handler {
 			return NewHandler(func(w http.ResponseWriter, r *http.Request) {
 				next.ServeHTTP(w, ns.onRouteServe(r))
 			})
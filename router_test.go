@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Nigel2392/mux"
@@ -314,3 +316,117 @@ func TestMuxNamespace(t *testing.T) {
 		return
 	}
 }
+
+func TestMethodNotAllowed(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var users = m.Handle(mux.GET, "/users/", h)
+	m.Handle(mux.DELETE, "/users/", h)
+	users.Handle(mux.PUT, "/<<id:int>>/", h)
+	m.Handle(mux.PATCH, "/users/<<id:int>>/", h)
+
+	var tests = []struct {
+		method string
+		path   string
+		status int
+		allow  string
+	}{
+		{mux.GET, "/users/", http.StatusOK, ""},
+		{mux.POST, "/users/", http.StatusMethodNotAllowed, "DELETE, GET"},
+		{mux.GET, "/users/1/", http.StatusMethodNotAllowed, "PATCH, PUT"},
+		{mux.GET, "/users/john/", http.StatusNotFound, ""},
+		{mux.GET, "/missing/", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		var req = httptest.NewRequest(test.method, test.path, nil)
+		var w = httptest.NewRecorder()
+		m.ServeHTTP(w, req)
+		if w.Code != test.status {
+			t.Errorf("%s %s: expected status %d, got %d", test.method, test.path, test.status, w.Code)
+		}
+		if allow := w.Header().Get("Allow"); allow != test.allow {
+			t.Errorf("%s %s: expected Allow %q, got %q", test.method, test.path, test.allow, allow)
+		}
+	}
+
+	m.MethodNotAllowedHandler = func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprint(w, w.Header().Get("Allow"))
+	}
+
+	var w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.POST, "/users/", nil))
+	if w.Code != http.StatusTeapot || w.Body.String() != "DELETE, GET" {
+		t.Errorf("Expected custom handler to be called with the Allow header, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestAutoOptionsHead(t *testing.T) {
+	var m = mux.New()
+	m.AutoOptions = true
+	m.AutoHead = true
+
+	m.HandleFunc(mux.GET, "/users/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		fmt.Fprint(w, "users")
+	})
+	m.HandleFunc(mux.POST, "/users/", func(w http.ResponseWriter, r *http.Request) {})
+	m.HandleFunc(mux.GET, "/explicit/", func(w http.ResponseWriter, r *http.Request) {})
+	m.HandleFunc(mux.HEAD, "/explicit/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Explicit", "head")
+	})
+	m.HandleFunc(mux.OPTIONS, "/explicit/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Explicit", "options")
+	})
+
+	var w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.OPTIONS, "/users/", nil))
+	if w.Code != http.StatusNoContent || w.Header().Get("Allow") != "GET, HEAD, OPTIONS, POST" {
+		t.Errorf("Expected 204 with Allow header, got %d %q", w.Code, w.Header().Get("Allow"))
+	}
+
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.HEAD, "/users/", nil))
+	if w.Code != http.StatusOK || w.Body.Len() != 0 {
+		t.Errorf("Expected 200 without a body, got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Length") != "5" || w.Header().Get("X-Method") != mux.HEAD {
+		t.Errorf("Expected the GET handler headers to be kept, got %v", w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.PUT, "/users/", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD, OPTIONS, POST" {
+		t.Errorf("Expected 405 with Allow header, got %d %q", w.Code, w.Header().Get("Allow"))
+	}
+
+	// The automatic responses run through the middleware of the mux.
+	m.Use(func(next mux.Handler) mux.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			next.ServeHTTP(w, r)
+		})
+	})
+	for _, method := range []string{mux.OPTIONS, mux.PUT} {
+		w = httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(method, "/users/", nil))
+		if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Errorf("Expected the automatic %s response to run the middleware, got %v", method, w.Header())
+		}
+	}
+
+	for _, method := range []string{mux.HEAD, mux.OPTIONS} {
+		w = httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest(method, "/explicit/", nil))
+		if w.Header().Get("X-Explicit") != strings.ToLower(method) {
+			t.Errorf("Expected the explicit %s route to be used, got %v", method, w.Header())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(mux.OPTIONS, "/missing/", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown path, got %d", w.Code)
+	}
+}
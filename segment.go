@@ -0,0 +1,305 @@
+package mux
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// parseSegment parses a single segment of a path into a PathPart.
+//
+// A segment is either static, a single variable (`<<name>>`), or a mix
+// of literal text and one or more variables (`<<name>>.<<ext>>`, `v<<version>>`).
+func parseSegment(m *Mux, segment string) *PathPart {
+	var syntax = m.pathSyntax()
+	var pieces = splitSegment(m, syntax, segment)
+	if len(pieces) == 1 {
+		return pieces[0]
+	}
+
+	if len(pieces) == 0 {
+		return &PathPart{Part: segment, syntax: syntax}
+	}
+
+	for _, piece := range pieces {
+		if piece.IsGlob || piece.Converter == PathConverter {
+			panic(fmt.Sprintf("glob cannot be combined with other text in segment %q", segment))
+		}
+		if piece.Optional {
+			panic(fmt.Sprintf("optional variable cannot be combined with other text in segment %q", segment))
+		}
+	}
+
+	return &PathPart{
+		Part:       segment,
+		IsVariable: true,
+		Pieces:     pieces,
+		syntax:     syntax,
+	}
+}
+
+// splitSegment splits a segment into its literal and variable pieces.
+func splitSegment(m *Mux, syntax *PathSyntax, segment string) []*PathPart {
+	var (
+		pieces = make([]*PathPart, 0, 1)
+		start  = syntax.VariableStart
+		end    = syntax.VariableEnd
+	)
+	for segment != "" {
+		var i = strings.Index(segment, start)
+		if i == -1 {
+			pieces = append(pieces, &PathPart{Part: segment, syntax: syntax})
+			break
+		}
+
+		if i > 0 {
+			pieces = append(pieces, &PathPart{Part: segment[:i], syntax: syntax})
+		}
+
+		var j = syntax.variableEnd(segment[i+len(start):])
+		if j == -1 {
+			panic(fmt.Sprintf("unterminated variable in segment %q", segment))
+		}
+
+		var inner = segment[i+len(start) : i+len(start)+j]
+		pieces = append(pieces, newVariablePart(m, syntax, inner))
+		segment = segment[min(len(segment), i+len(start)+j+len(end)):]
+	}
+	return pieces
+}
+
+// newVariablePart creates the part for the text between the delimiters of a variable.
+func newVariablePart(m *Mux, syntax *PathSyntax, inner string) *PathPart {
+	var part = &PathPart{
+		Part:       inner,
+		IsVariable: true,
+		syntax:     syntax,
+	}
+
+	if syntax.ConverterDelim != "" {
+		if name, constraint, ok := strings.Cut(inner, syntax.ConverterDelim); ok {
+			part.Part = name
+			if isConverterName(constraint) {
+				part.Converter = constraint
+				part.converter, _ = m.lookupConverter(constraint)
+			} else {
+				part.Pattern = constraint
+				part.converter = newPatternConverter(constraint)
+			}
+		}
+	}
+
+	// An optional variable with a default, I.E. `<<page?1>>`.
+	if syntax.OptionalMarker != "" {
+		if name, def, ok := strings.Cut(part.Part, syntax.OptionalMarker); ok {
+			part.Part = name
+			part.Optional = true
+			part.Default = def
+		}
+	}
+
+	// A named catch-all, I.E. `<<path...>>`.
+	if syntax.CatchAllSuffix != "" && len(part.Part) > len(syntax.CatchAllSuffix) && strings.HasSuffix(part.Part, syntax.CatchAllSuffix) {
+		part.Part = strings.TrimSuffix(part.Part, syntax.CatchAllSuffix)
+		part.IsGlob = true
+	}
+
+	return part
+}
+
+// IsMixed reports whether the part mixes literal text and variables in a single segment.
+func (p *PathPart) IsMixed() bool {
+	return len(p.Pieces) > 0
+}
+
+// Names returns the names of the variables captured by this part, in order.
+func (p *PathPart) Names() []string {
+	switch {
+	case p.IsMixed():
+		var names = make([]string, 0, len(p.Pieces))
+		for _, piece := range p.Pieces {
+			if piece.IsVariable {
+				names = append(names, piece.Part)
+			}
+		}
+		return names
+	case p.IsVariable || p.IsGlob:
+		return []string{p.Name()}
+	}
+	return nil
+}
+
+// String returns the part as it would be written in a path.
+func (p *PathPart) String() string {
+	switch {
+	case p.IsMixed():
+		var b strings.Builder
+		for _, piece := range p.Pieces {
+			b.WriteString(piece.String())
+		}
+		return b.String()
+	case p.IsVariable:
+		var syntax = p.pathSyntax()
+		var b strings.Builder
+		b.WriteString(syntax.VariableStart)
+		b.WriteString(p.Part)
+		if p.IsGlob && p.Converter != PathConverter {
+			b.WriteString(syntax.CatchAllSuffix)
+		}
+		if p.Optional {
+			b.WriteString(syntax.OptionalMarker)
+			b.WriteString(p.Default)
+		}
+		if c := p.Constraint(); c != "" {
+			b.WriteString(syntax.ConverterDelim)
+			b.WriteString(c)
+		}
+		b.WriteString(syntax.VariableEnd)
+		return b.String()
+	}
+	return p.Part
+}
+
+// pathSyntax returns the syntax the part was written in.
+func (p *PathPart) pathSyntax() *PathSyntax {
+	if p.syntax == nil {
+		return DefaultPathSyntax()
+	}
+	return p.syntax
+}
+
+// signature identifies parts which match the same segments,
+// regardless of the names of their variables or the syntax they were written in.
+func (p *PathPart) signature() string {
+	switch {
+	case p.IsMixed():
+		var b strings.Builder
+		for _, piece := range p.Pieces {
+			b.WriteString(piece.signature())
+		}
+		return b.String()
+	case p.IsGlob:
+		return "\x00*" + p.Constraint() + "\x00"
+	case p.IsVariable && p.Optional:
+		return "\x00" + p.Constraint() + "\x00?"
+	case p.IsVariable:
+		return "\x00" + p.Constraint() + "\x00"
+	}
+	return p.Part
+}
+
+// capture matches a single segment against the part,
+// appending the values of its variables to the captures.
+//
+// If fold is true, literal text is compared case-insensitively.
+func (p *PathPart) capture(segment string, captures []string, fold bool) ([]string, bool) {
+	switch {
+	case p.IsMixed():
+		return capturePieces(p.Pieces, segment, captures, fold)
+	case p.IsVariable:
+		if segment == "" || !p.Validate(segment) {
+			return captures, false
+		}
+		return append(captures, segment), true
+	}
+	if fold {
+		return captures, strings.EqualFold(p.Part, segment)
+	}
+	return captures, p.Part == segment
+}
+
+// capturePieces matches the pieces of a mixed segment, backtracking when a variable
+// captured too much. Variables are greedy, the longest valid value is tried first.
+func capturePieces(pieces []*PathPart, segment string, captures []string, fold bool) ([]string, bool) {
+	if len(pieces) == 0 {
+		return captures, segment == ""
+	}
+
+	var piece = pieces[0]
+	if !piece.IsVariable {
+		if !hasPrefix(segment, piece.Part, fold) {
+			return captures, false
+		}
+		return capturePieces(pieces[1:], segment[len(piece.Part):], captures, fold)
+	}
+
+	for end := len(segment); end > 0; end-- {
+		var value = segment[:end]
+		if !piece.Validate(value) {
+			continue
+		}
+		if c, ok := capturePieces(pieces[1:], segment[end:], append(captures, value), fold); ok {
+			return c, true
+		}
+	}
+
+	return captures, false
+}
+
+func hasPrefix(s, prefix string, fold bool) bool {
+	if fold {
+		return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+	}
+	return strings.HasPrefix(s, prefix)
+}
+
+// reverse writes the part to the builder, consuming variables starting at index.
+//
+// It returns the index of the next unused variable.
+func (p *PathPart) reverse(b *strings.Builder, variables []interface{}, index int) (int, error) {
+	if !p.IsMixed() {
+		if !p.IsVariable {
+			b.WriteString(p.Part)
+			return index, nil
+		}
+		return writeVariable(b, p, variables, index)
+	}
+
+	var err error
+	for _, piece := range p.Pieces {
+		if !piece.IsVariable {
+			b.WriteString(piece.Part)
+			continue
+		}
+		if index, err = writeVariable(b, piece, variables, index); err != nil {
+			return index, err
+		}
+	}
+	return index, nil
+}
+
+func writeVariable(b *strings.Builder, part *PathPart, variables []interface{}, index int) (int, error) {
+	if index >= len(variables) {
+		return index, ErrNotEnoughVariables
+	}
+	var value = fmt.Sprint(variables[index])
+	if !part.Validate(value) {
+		return index, ErrInvalidVariable
+	}
+	b.WriteString(url.PathEscape(value))
+	return index + 1, nil
+}
+
+// escapeSegments escapes every segment of the path, keeping the slashes between them.
+func escapeSegments(path string) string {
+	var segments = strings.Split(path, URL_DELIM)
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, URL_DELIM)
+}
+
+// unescapeSegments unescapes the segments of a path in place.
+//
+// Segments which are not validly escaped are left as they are.
+func unescapeSegments(segments []string) []string {
+	for i, segment := range segments {
+		if !strings.Contains(segment, "%") {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+	return segments
+}
@@ -0,0 +1,93 @@
+package mux_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestMixedSegments(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+
+	var (
+		file    = m.Handle(mux.GET, "/files/<<name>>.<<ext>>", h)
+		version = m.Handle(mux.GET, "/v<<version:uint>>/users/", h)
+		rng     = m.Handle(mux.GET, "/range/<<from:int>>-<<to:int>>/", h)
+		plain   = m.Handle(mux.GET, "/files/<<name>>", h)
+	)
+
+	var tests = []struct {
+		path     string
+		expected *mux.Route
+		vars     mux.Variables
+	}{
+		{"/files/report.pdf", file, mux.Variables{"name": {"report"}, "ext": {"pdf"}}},
+		{"/files/archive.tar.gz", file, mux.Variables{"name": {"archive.tar"}, "ext": {"gz"}}},
+		{"/files/README", plain, mux.Variables{"name": {"README"}}},
+		{"/files/.hidden", plain, mux.Variables{"name": {".hidden"}}},
+		{"/v2/users/", version, mux.Variables{"version": {"2"}}},
+		{"/vx/users/", nil, nil},
+		{"/range/1-10/", rng, mux.Variables{"from": {"1"}, "to": {"10"}}},
+		{"/range/-5--1/", rng, mux.Variables{"from": {"-5"}, "to": {"-1"}}},
+		{"/range/a-b/", nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var route, vars = m.Match(mux.GET, test.path)
+			if route != test.expected {
+				t.Fatalf("Expected route %v, got %v", test.expected, route)
+			}
+			if test.expected != nil && !reflect.DeepEqual(vars, test.vars) {
+				t.Fatalf("Expected variables %v, got %v", test.vars, vars)
+			}
+
+			if test.expected == nil {
+				return
+			}
+
+			// PathInfo.Match should agree with the compiled tree.
+			var ok, _, v = route.Path.Match(mux.SplitPath(test.path), 0, nil)
+			if !ok || !reflect.DeepEqual(v, test.vars) {
+				t.Fatalf("Expected PathInfo.Match to return %v, got %v (%v)", test.vars, v, ok)
+			}
+		})
+	}
+
+	if s := file.Path.String(); s != "/files/<<name>>.<<ext>>" {
+		t.Fatalf("Expected path to round-trip, got %s", s)
+	}
+}
+
+func TestMixedSegmentsReverse(t *testing.T) {
+	var tests = []struct {
+		path     string
+		args     []interface{}
+		expected string
+	}{
+		{"/files/<<name>>.<<ext>>", []interface{}{"report", "pdf"}, "/files/report.pdf/"},
+		{"/v<<version:uint>>/users/<<id>>", []interface{}{2, "john"}, "/v2/users/john/"},
+		{"/range/<<from:int>>-<<to:int>>/<<page>>/", []interface{}{1, 10, 3}, "/range/1-10/3/"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var info = mux.NewPathInfo(nil, test.path)
+			var result, err = info.Reverse(test.args...)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result != test.expected {
+				t.Fatalf("Expected %s, got %s", test.expected, result)
+			}
+		})
+	}
+
+	var info = mux.NewPathInfo(nil, "/files/<<name>>.<<ext>>")
+	if _, err := info.Reverse("report"); err != mux.ErrNotEnoughVariables {
+		t.Fatalf("Expected %v, got %v", mux.ErrNotEnoughVariables, err)
+	}
+}
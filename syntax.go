@@ -0,0 +1,98 @@
+package mux
+
+import "strings"
+
+// PathSyntax describes how variables and globs are written in the paths of routes.
+//
+// The syntax is used when a path is parsed, changing it does not
+// affect routes which were already registered.
+type PathSyntax struct {
+	// The text which starts and ends a variable, I.E. `<<` and `>>`.
+	//
+	// If VariableEnd is empty, a variable runs until the end of its segment.
+	VariableStart string
+	VariableEnd   string
+
+	// The text which separates the name of a variable from its converter or pattern.
+	//
+	// If it is empty, variables cannot have a converter or pattern.
+	ConverterDelim string
+
+	// The segment which captures the remainder of the path.
+	Glob string
+
+	// The suffix of the name of a variable which makes it a named glob, I.E. `<<path...>>`.
+	//
+	// If it is empty, globs cannot be named.
+	CatchAllSuffix string
+
+	// The text after the name of a variable which makes it optional, I.E. `<<page?>>`.
+	// The text after the marker is the default value, I.E. `<<page?1>>`.
+	//
+	// If it is empty, variables cannot be optional.
+	OptionalMarker string
+}
+
+// Built-in presets for the syntax of paths.
+var (
+	// AngleSyntax writes variables as `<<id:int>>`, this is the default.
+	AngleSyntax = PathSyntax{VariableStart: "<<", VariableEnd: ">>", ConverterDelim: ":", Glob: "*", CatchAllSuffix: "...", OptionalMarker: "?"}
+
+	// BraceSyntax writes variables as `{id:int}`.
+	BraceSyntax = PathSyntax{VariableStart: "{", VariableEnd: "}", ConverterDelim: ":", Glob: "*", CatchAllSuffix: "...", OptionalMarker: "?"}
+
+	// ColonSyntax writes variables as `:id` or `:id|int`,
+	// a variable runs until the end of its segment.
+	ColonSyntax = PathSyntax{VariableStart: ":", VariableEnd: "", ConverterDelim: "|", Glob: "*", CatchAllSuffix: "...", OptionalMarker: "?"}
+)
+
+// DefaultPathSyntax returns the syntax described by VARIABLE_DELIMS, CONVERTER_DELIM and GLOB,
+// named globs end in `...` and optional variables in `?`.
+//
+// It is used by a Mux which does not have a syntax set.
+func DefaultPathSyntax() *PathSyntax {
+	return &PathSyntax{
+		VariableStart:  VARIABLE_DELIMS[0],
+		VariableEnd:    VARIABLE_DELIMS[1],
+		ConverterDelim: CONVERTER_DELIM,
+		Glob:           GLOB,
+		CatchAllSuffix: "...",
+		OptionalMarker: "?",
+	}
+}
+
+// pathSyntax returns the syntax used to parse paths for the mux.
+func (r *Mux) pathSyntax() *PathSyntax {
+	if r == nil || r.Syntax == nil {
+		return DefaultPathSyntax()
+	}
+	return r.Syntax
+}
+
+// variableEnd returns the index of the end of the variable which starts the text,
+// or -1 if the variable is not terminated.
+//
+// Variables may contain balanced delimiters, I.E. `{id:[0-9]{3}}`.
+func (s *PathSyntax) variableEnd(text string) int {
+	if s.VariableEnd == "" {
+		return len(text)
+	}
+
+	var depth int
+	for i := 0; i < len(text); {
+		switch {
+		case strings.HasPrefix(text[i:], s.VariableEnd):
+			if depth == 0 {
+				return i
+			}
+			depth--
+			i += len(s.VariableEnd)
+		case strings.HasPrefix(text[i:], s.VariableStart):
+			depth++
+			i += len(s.VariableStart)
+		default:
+			i++
+		}
+	}
+	return -1
+}
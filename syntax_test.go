@@ -0,0 +1,94 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func TestPathSyntax(t *testing.T) {
+	var tests = []struct {
+		syntax   *mux.PathSyntax
+		path     string
+		glob     string
+		request  string
+		expected string
+	}{
+		{nil, "/users/<<id:int>>/<<year:[0-9]{4}>>/", "/static/*", "/users/1/2024/", "map[id:[1] year:[2024]]"},
+		{&mux.AngleSyntax, "/users/<<id:int>>/<<year:[0-9]{4}>>/", "/static/*", "/users/1/2024/", "map[id:[1] year:[2024]]"},
+		{&mux.BraceSyntax, "/users/{id:int}/{year:[0-9]{4}}/", "/static/*", "/users/1/2024/", "map[id:[1] year:[2024]]"},
+		{&mux.ColonSyntax, "/users/:id|int/:year|[0-9]{4}/", "/static/*", "/users/1/2024/", "map[id:[1] year:[2024]]"},
+		{&mux.PathSyntax{VariableStart: "[", VariableEnd: "]", ConverterDelim: "=", Glob: "**"}, "/users/[id=int]/[year=[0-9]{4}]/", "/static/**", "/users/1/2024/", "map[id:[1] year:[2024]]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			var m = mux.New()
+			m.Syntax = test.syntax
+			var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, mux.Vars(r))
+			})
+			var route = m.Handle(mux.GET, test.path, h, "users")
+			var static = m.Handle(mux.GET, test.glob, h, "static")
+
+			if route.Path.String()+"/" != test.path {
+				t.Fatalf("Expected the path to round-trip as %q, got %q", test.path, route.Path.String())
+			}
+			if static.Path.String() != test.glob || !static.Path.IsGlob {
+				t.Fatalf("Expected a glob %q, got %q", test.glob, static.Path.String())
+			}
+
+			var w = httptest.NewRecorder()
+			m.ServeHTTP(w, httptest.NewRequest(mux.GET, test.request, nil))
+			if w.Body.String() != test.expected {
+				t.Fatalf("Expected %q, got %q", test.expected, w.Body.String())
+			}
+
+			if path, err := m.Reverse("users", 1, 2024); err != nil || path != "/users/1/2024/" {
+				t.Fatalf("Expected /users/1/2024/, got %q (%v)", path, err)
+			}
+			if _, err := m.Reverse("users", 1, 24); err != mux.ErrInvalidVariable {
+				t.Fatalf("Expected %v, got %v", mux.ErrInvalidVariable, err)
+			}
+		})
+	}
+}
+
+func TestPathSyntaxAddRoute(t *testing.T) {
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var m = mux.New()
+	m.Syntax = &mux.BraceSyntax
+
+	var parent = mux.NewRoute(mux.ANY, "/x/{id:int}/", nil)
+	parent.AddRoute(mux.NewRoute(mux.GET, "/{name}/", h))
+	m.AddRoute(parent)
+	m.Group("/g/", func(g mux.Multiplexer) {
+		g.AddRoute(mux.NewRoute(mux.GET, "/{id}/", h))
+	})
+
+	if _, vars := m.Match(mux.GET, "/x/1/a/"); vars.Get("id") != "1" || vars.Get("name") != "a" {
+		t.Fatalf("Expected the route to be parsed with the syntax of the mux, got %v", vars)
+	}
+	if _, vars := m.Match(mux.GET, "/g/2/"); vars.Get("id") != "2" {
+		t.Fatalf("Expected the route of the group to be parsed with the syntax of the mux, got %v", vars)
+	}
+}
+
+func TestPathSyntaxIsolated(t *testing.T) {
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var angle, brace = mux.New(), mux.New()
+	brace.Syntax = &mux.BraceSyntax
+
+	angle.Handle(mux.GET, "/{literal}/<<id>>/", h)
+	brace.Handle(mux.GET, "/<<literal>>/{id}/", h)
+
+	if _, vars := angle.Match(mux.GET, "/{literal}/1/"); vars.Get("id") != "1" {
+		t.Fatalf("Expected braces to be literal text in the angle mux, got %v", vars)
+	}
+	if _, vars := brace.Match(mux.GET, "/<<literal>>/1/"); vars.Get("id") != "1" {
+		t.Fatalf("Expected angles to be literal text in the brace mux, got %v", vars)
+	}
+}
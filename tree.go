@@ -0,0 +1,554 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// routeTree is a prefix tree compiled from the routes registered on a Mux.
+//
+// Every node represents a position in the (split) path, edges are
+// separated into static edges (looked up by the exact segment),
+// variable edges (one per converter) and glob edges.
+//
+// The tree is immutable once built, mutations of the route table
+// invalidate it and a new tree is built on the next match.
+type routeTree struct {
+	root *treeNode
+
+	// The middleware of the mux, which the fallback responses run through as well.
+	middleware []Middleware
+
+	// Whether any route matches case-insensitively.
+	fold bool
+}
+
+type treeNode struct {
+	static    map[string]*treeNode
+	fold      map[string][]string // the keys of static, by their lowercase form
+	variables []*treeNode
+	leaves    []*treeLeaf // routes which end at this node
+	globs     []*treeLeaf // routes which end in a glob at this node
+
+	// The part which leads to this node, if it was reached through a variable edge.
+	part *PathPart
+}
+
+// matchInput holds the properties of a request which routes are matched against.
+type matchInput struct {
+	method string
+	host   string // Host patterns are not checked if empty.
+	path   []string
+	req    *http.Request // Matchers are not checked if nil.
+
+	// Whether the path ended with a slash, and whether
+	// this has to agree with the path of the route.
+	slash       bool
+	strictSlash bool
+
+	// Whether static text is matched case-insensitively for all routes.
+	fold bool
+}
+
+// treeLeaf is a route stored in the tree.
+//
+// The order is the position the route would have been visited in
+// by walking the route table depth-first, this is used to break
+// ties between routes which are equally specific.
+type treeLeaf struct {
+	route *Route
+	order int
+
+	// The handler of the route wrapped in its middleware chain,
+	// and the full pattern of the route.
+	handler Handler
+	pattern string
+
+	parts []*PathPart
+	host  *HostInfo
+
+	// The parts after a glob which does not end the path.
+	suffix []*PathPart
+
+	// The optional parts which were left out of the parts.
+	omitted []*PathPart
+
+	// The request matchers of the route and its parents, copied
+	// when the tree is built so changes to the route do not race with matching.
+	matchers []RequestMatcher
+
+	// Whether the path of the route was defined with a trailing slash.
+	slash bool
+
+	// Whether the route matches static text case-insensitively.
+	fold bool
+}
+
+// treeCandidate is a leaf which matched the path.
+type treeCandidate struct {
+	leaf     *treeLeaf
+	captures []string
+	rest     []string
+
+	// Whether static text only matched when ignoring case.
+	folded bool
+}
+
+func newTreeNode() *treeNode {
+	return &treeNode{}
+}
+
+// buildTree compiles the routes into a routeTree,
+// composing their handlers with the middleware of the mux.
+func buildTree(routes []*Route, middleware []Middleware) *routeTree {
+	var t = &routeTree{root: newTreeNode(), middleware: slices.Clone(middleware)}
+	var order int
+	var walk func(rt *Route)
+	walk = func(rt *Route) {
+		t.insert(rt, order, middleware)
+		order++
+		for _, child := range rt.Children {
+			walk(child)
+		}
+	}
+	for _, rt := range routes {
+		walk(rt)
+	}
+	return t
+}
+
+func (t *routeTree) insert(rt *Route, order int, middleware []Middleware) {
+	if rt.Path == nil || rt.Handler == nil {
+		return
+	}
+
+	var handler = rt.chain(middleware)
+	var pattern = rt.Path.String()
+
+	var parts = pathParts(rt.Path)
+	var required = len(parts)
+	for required > 0 && parts[required-1].Optional {
+		required--
+	}
+
+	// A route with optional parts is stored once for every number of optional parts it can be matched with.
+	for n := required; n <= len(parts); n++ {
+		t.insertLeaf(&treeLeaf{
+			route:   rt,
+			order:   order,
+			handler: handler,
+			pattern: pattern,
+			parts:   parts[:n],
+			omitted: parts[n:],
+			host:    rt.HostPattern(),
+
+			matchers: rt.routeMatchers(),
+			slash:    rt.Path.trailingSlash(),
+			fold:     rt.ignoresCase(),
+		})
+	}
+}
+
+func (t *routeTree) insertLeaf(leaf *treeLeaf) {
+	t.fold = t.fold || leaf.fold
+
+	var n = t.root
+	for i, part := range leaf.parts {
+		switch {
+		case part.IsGlob:
+			leaf.suffix = leaf.parts[i+1:]
+			n.globs = append(n.globs, leaf)
+			return
+		case part.IsVariable:
+			n = n.variableEdge(part)
+		default:
+			if n.static == nil {
+				n.static = make(map[string]*treeNode)
+			}
+			var next, ok = n.static[part.Part]
+			if !ok {
+				if n.fold == nil {
+					n.fold = make(map[string][]string)
+				}
+				var key = strings.ToLower(part.Part)
+				next = newTreeNode()
+				n.static[part.Part] = next
+				n.fold[key] = append(n.fold[key], part.Part)
+			}
+			n = next
+		}
+	}
+
+	n.leaves = append(n.leaves, leaf)
+}
+
+// variableEdge returns the node for the variable part, variables
+// which match the same segments share an edge.
+func (n *treeNode) variableEdge(part *PathPart) *treeNode {
+	var signature = part.signature()
+	for _, next := range n.variables {
+		if next.part.signature() == signature {
+			return next
+		}
+	}
+	var next = newTreeNode()
+	next.part = part
+	n.variables = append(n.variables, next)
+	return next
+}
+
+// collect walks the tree and gathers every leaf which matches the path.
+//
+// If fold is true, static text which only matches when ignoring case is followed as well,
+// the candidates found this way are marked as folded.
+//
+// The candidates are added to the state, the captures are a stack
+// which is shared by the branches of the tree.
+func (n *treeNode) collect(st *matchState, path []string, i int, fold, folded bool, captures []string) {
+	for _, leaf := range n.globs {
+		if len(leaf.suffix) > 0 {
+			leaf.collectSuffix(st, path, i, fold, folded, captures)
+			continue
+		}
+		st.candidate(leaf, captures, path[i:], folded)
+	}
+
+	if i == len(path) {
+		for _, leaf := range n.leaves {
+			st.candidate(leaf, captures, nil, folded)
+		}
+		return
+	}
+
+	var seg = path[i]
+	if next, ok := n.static[seg]; ok {
+		next.collect(st, path, i+1, fold, folded, captures)
+	}
+
+	if fold {
+		for _, key := range n.fold[strings.ToLower(seg)] {
+			if key != seg {
+				n.static[key].collect(st, path, i+1, fold, true, captures)
+			}
+		}
+	}
+
+	if seg == "" {
+		return
+	}
+
+	for _, next := range n.variables {
+		if c, ok := next.part.capture(seg, captures, false); ok {
+			next.collect(st, path, i+1, fold, folded, c)
+		} else if fold && next.part.IsMixed() {
+			if c, ok := next.part.capture(seg, captures, true); ok {
+				next.collect(st, path, i+1, fold, true, c)
+			}
+		}
+	}
+}
+
+// collectSuffix matches a glob which is followed by other parts, starting at segment i.
+//
+// The parts after the glob each match a single segment, so the glob
+// captures everything up to them; it has to capture at least one segment.
+func (l *treeLeaf) collectSuffix(st *matchState, path []string, i int, fold, folded bool, captures []string) {
+	var end = len(path) - len(l.suffix)
+	if end <= i {
+		return
+	}
+
+	// The captures of the suffix are pushed onto the stack, st.candidate copies them.
+	for j, part := range l.suffix {
+		var seg = path[end+j]
+		var c, ok = part.capture(seg, captures, false)
+		if !ok && fold && (!part.IsVariable || part.IsMixed()) {
+			c, ok = part.capture(seg, captures, true)
+			folded = folded || ok
+		}
+		if !ok {
+			return
+		}
+		captures = c
+	}
+
+	st.candidate(l, captures, path[i:end], folded)
+}
+
+// match returns the route which matches the method, host and path of the input.
+//
+// When multiple routes match, the most specific route is returned.
+// Routes are compared segment by segment from left to right, static segments
+// win over mixed segments, which win over constrained variables, which win
+// over plain variables, which win over globs.
+// Routes with a host pattern win over routes without one, and
+// routes with matchers win over equally specific routes without matchers.
+// If the host or request is not known, the routes without a host pattern or
+// matchers win instead, as the host pattern or matchers are not checked.
+// Routes which are equally specific are ordered by registration
+// (depth-first, parents before their children).
+//
+// If no route matches, the methods of the routes which do match
+// the path are returned, sorted and without duplicates.
+func (t *routeTree) match(st *matchState, in matchInput) (*Route, Variables, []string) {
+	var c, vars, allowed = t.lookup(st, in)
+	if c == nil {
+		return nil, nil, allowed
+	}
+	return c.leaf.route, vars, nil
+}
+
+// lookup is like match, but returns the candidate which matched instead of its route.
+//
+// The candidate is only valid until the state is released, as are
+// the variables if the state reuses them.
+func (t *routeTree) lookup(st *matchState, in matchInput) (*treeCandidate, Variables, []string) {
+	st.candidates = st.candidates[:0]
+	st.arena = st.arena[:0]
+	t.root.collect(st, in.path, 0, in.fold || t.fold, false, st.captures[:0])
+	if len(st.candidates) == 0 {
+		return nil, nil, nil
+	}
+
+	var candidates = st.candidates
+	var order = candidateOrder{host: in.host != "", req: in.req != nil}
+	slices.SortFunc(candidates, order.compare)
+
+	for i := range candidates {
+		var c = &candidates[i]
+		if !routeMatched(true, in.method, c.leaf.route) || !c.matches(in) {
+			continue
+		}
+
+		var vars, ok = c.variables(st, in.host)
+		if ok {
+			return c, vars, nil
+		}
+	}
+
+	var allowed []string
+	for i := range candidates {
+		var c = &candidates[i]
+		if routeMatched(true, in.method, c.leaf.route) || slices.Contains(allowed, c.leaf.route.Method) || !c.matches(in) {
+			continue
+		}
+
+		if _, ok := c.variables(st, in.host); ok {
+			allowed = append(allowed, c.leaf.route.Method)
+		}
+	}
+	slices.Sort(allowed)
+
+	return nil, nil, allowed
+}
+
+// glob reports whether the route of the leaf ends in a glob.
+func (l *treeLeaf) glob() bool {
+	return len(l.parts) > 0 && l.parts[len(l.parts)-1].IsGlob
+}
+
+// candidateOrder orders candidates by specificity, then by registration order, see match.
+//
+// Host patterns and matchers are only preferred if the host and request are known,
+// otherwise they are not checked, and the routes without them are preferred instead.
+type candidateOrder struct {
+	host bool
+	req  bool
+}
+
+func (o candidateOrder) compare(a, b treeCandidate) int {
+	var ah, bh = a.leaf.host != nil, b.leaf.host != nil
+	if o.host && ah != bh {
+		if ah {
+			return -1
+		}
+		return 1
+	}
+
+	var ap, bp = a.leaf.parts, b.leaf.parts
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		if d := partRank(ap[i]) - partRank(bp[i]); d != 0 {
+			return d
+		}
+	}
+
+	// Both matched the same path, the longer one ends in a glob which matched nothing.
+	if d := len(ap) - len(bp); d != 0 {
+		return d
+	}
+
+	if a.folded != b.folded {
+		if b.folded {
+			return -1
+		}
+		return 1
+	}
+
+	if !o.host && ah != bh {
+		if bh {
+			return -1
+		}
+		return 1
+	}
+
+	var am, bm = len(a.leaf.matchers) > 0, len(b.leaf.matchers) > 0
+	if am != bm {
+		if am == o.req {
+			return -1
+		}
+		return 1
+	}
+
+	return a.leaf.order - b.leaf.order
+}
+
+// partRank returns the precedence of a part, lower ranks are more specific.
+func partRank(p *PathPart) int {
+	switch {
+	case p.IsGlob:
+		return 4
+	case p.IsMixed():
+		return 1
+	case p.IsVariable && p.converter != nil:
+		return 2
+	case p.IsVariable:
+		return 3
+	}
+	return 0
+}
+
+// matches reports whether the input satisfies the case, trailing slash and matchers of the candidate.
+func (c *treeCandidate) matches(in matchInput) bool {
+	if c.folded && !in.fold && !c.leaf.fold {
+		return false
+	}
+	if in.strictSlash && !c.leaf.glob() && len(in.path) > 0 && c.leaf.slash != in.slash {
+		return false
+	}
+	if in.req == nil {
+		return true
+	}
+	for _, matcher := range c.leaf.matchers {
+		if !matcher(in.req) {
+			return false
+		}
+	}
+	return true
+}
+
+// variables builds the variables for the candidate from the host and the captured segments.
+//
+// The variables are stored in the storage of the state if it reuses them.
+func (c *treeCandidate) variables(st *matchState, host string) (Variables, bool) {
+	var (
+		vars Variables
+		idx  int
+	)
+	if c.leaf.host != nil && host != "" {
+		var hostVars, ok = c.leaf.host.Match(host)
+		if !ok {
+			return nil, false
+		}
+		if len(hostVars) > 0 {
+			vars = st.newVariables()
+			maps.Copy(vars, hostVars)
+		}
+	}
+
+	for _, part := range c.leaf.parts {
+		switch {
+		case part.IsGlob:
+			var resolver = c.leaf.route.Path.Resolver
+			if resolver == nil || len(c.leaf.suffix) > 0 {
+				if part.converter != nil && !part.Validate(strings.Join(c.rest, URL_DELIM)) {
+					return nil, false
+				}
+				if vars == nil {
+					vars = st.newVariables()
+				}
+				vars[part.Name()] = st.segmentsOf(c.rest)
+				continue
+			}
+
+			var varsWasNil = vars == nil
+			if vars == nil {
+				vars = st.newVariables()
+			}
+			ok, v := resolver.Match(vars, st.segmentsOf(c.rest))
+			if !ok {
+				return nil, false
+			}
+			if !varsWasNil {
+				maps.Copy(vars, v)
+			} else {
+				vars = v
+			}
+			return vars, true
+		case part.IsVariable:
+			if vars == nil {
+				vars = st.newVariables()
+			}
+			for _, name := range part.Names() {
+				vars[name] = st.value(vars[name], c.captures[idx])
+				idx++
+			}
+		}
+	}
+
+	for _, part := range c.leaf.omitted {
+		if part.Default == "" {
+			continue
+		}
+		if vars == nil {
+			vars = st.newVariables()
+		}
+		vars[part.Part] = st.value(vars[part.Part], part.Default)
+	}
+	return vars, true
+}
+
+// canonicalPath returns the escaped path of the route with its static text in lowercase,
+// and the captured values filled in as they were requested.
+func (c *treeCandidate) canonicalPath(slash bool) string {
+	var (
+		b   strings.Builder
+		idx int
+	)
+	for _, part := range c.leaf.parts {
+		b.WriteString(URL_DELIM)
+		switch {
+		case part.IsGlob:
+			for i, segment := range c.rest {
+				if i > 0 {
+					b.WriteString(URL_DELIM)
+				}
+				b.WriteString(url.PathEscape(segment))
+			}
+		case part.IsMixed():
+			for _, piece := range part.Pieces {
+				if !piece.IsVariable {
+					b.WriteString(strings.ToLower(piece.Part))
+					continue
+				}
+				b.WriteString(url.PathEscape(c.captures[idx]))
+				idx++
+			}
+		case part.IsVariable:
+			b.WriteString(url.PathEscape(c.captures[idx]))
+			idx++
+		default:
+			b.WriteString(strings.ToLower(part.Part))
+		}
+	}
+
+	if b.Len() == 0 || slash && !c.leaf.glob() {
+		b.WriteString(URL_DELIM)
+	}
+	return b.String()
+}
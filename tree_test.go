@@ -0,0 +1,273 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+// linearMatch mirrors the matching behaviour of walking the route table in order.
+//
+// The results only agree with Mux.Match when no two routes with a different
+// specificity match the same path, see TestMatchPrecedence.
+func linearMatch(routes []*mux.Route, method, path string) (*mux.Route, mux.Variables) {
+	var parts = mux.SplitPath(path)
+	for _, route := range routes {
+		if rt, ok, vars := route.Match(method, parts); ok {
+			return rt, vars
+		}
+	}
+	return nil, nil
+}
+
+func TestTreeMatchesLinear(t *testing.T) {
+	var (
+		m      = mux.New()
+		h      = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+		topLvl []*mux.Route
+		handle = func(method, path string) *mux.Route {
+			var rt = m.Handle(method, path, h)
+			topLvl = append(topLvl, rt)
+			return rt
+		}
+	)
+
+	handle(mux.GET, "/")
+	handle(mux.GET, "/users/<<id>>/")
+	handle(mux.POST, "/users/")
+	var users = handle(mux.GET, "/users/")
+	users.Handle(mux.GET, "/<<id>>/posts/", h)
+	users.Handle(mux.DELETE, "/<<id>>/", h)
+	var files = handle(mux.ANY, "/files/")
+	files.Handle(mux.GET, "/<<name>>/<<name>>/", h)
+	files.Handle(mux.GET, "/*", h)
+	handle(mux.GET, "/a/<<b>>/c/")
+	handle(mux.GET, "/*")
+
+	var tests = []struct {
+		method string
+		path   string
+	}{
+		{mux.GET, "/"},
+		{mux.GET, "/users/"},
+		{mux.POST, "/users/"},
+		{mux.PUT, "/users/"},
+		{mux.GET, "/users/42/"},
+		{mux.DELETE, "/users/42/"},
+		{mux.GET, "/users/42/posts/"},
+		{mux.GET, "/files/"},
+		{mux.POST, "/files/"},
+		{mux.GET, "/files/a/b/"},
+		{mux.GET, "/files/a/b/c/"},
+		{mux.GET, "/a/b/c/"},
+		{mux.GET, "/a/x/c/"},
+		{mux.GET, "/does/not/exist/"},
+		{mux.ANY, "/users/42/"},
+		{mux.GET, "//users/"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s-%s", test.method, test.path), func(t *testing.T) {
+			var expectedRoute, expectedVars = linearMatch(topLvl, test.method, test.path)
+			var route, vars = m.Match(test.method, test.path)
+			if route != expectedRoute {
+				t.Fatalf("Expected route %v, got %v", expectedRoute, route)
+			}
+			if !reflect.DeepEqual(vars, expectedVars) {
+				t.Fatalf("Expected variables %v, got %v", expectedVars, vars)
+			}
+		})
+	}
+}
+
+func TestMatchPrecedence(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+
+	var (
+		glob     = m.Handle(mux.GET, "/users/*", h)
+		byName   = m.Handle(mux.GET, "/users/<<name>>/", h)
+		byID     = m.Handle(mux.GET, "/users/<<id:int>>/", h)
+		file     = m.Handle(mux.GET, "/users/<<name>>.<<ext>>/", h)
+		me       = m.Handle(mux.GET, "/users/me/", h)
+		varFirst = m.Handle(mux.GET, "/a/<<b>>/c/", h)
+		varLast  = m.Handle(mux.GET, "/a/b/<<c>>/", h)
+		first    = m.Handle(mux.GET, "/tie/<<a>>/", h)
+		_        = m.Handle(mux.POST, "/tie/<<b>>/", h)
+		anyTie   = m.Handle(mux.ANY, "/tie/<<c>>/", h)
+	)
+
+	var tests = []struct {
+		method   string
+		path     string
+		expected *mux.Route
+	}{
+		{mux.GET, "/users/me/", me},
+		{mux.GET, "/users/42/", byID},
+		{mux.GET, "/users/john/", byName},
+		{mux.GET, "/users/john.json/", file},
+		{mux.GET, "/users/john/posts/", glob},
+		{mux.GET, "/a/b/c/", varLast},
+		{mux.GET, "/a/x/c/", varFirst},
+		{mux.GET, "/tie/x/", first},
+		{mux.PUT, "/tie/x/", anyTie},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s-%s", test.method, test.path), func(t *testing.T) {
+			var route, _ = m.Match(test.method, test.path)
+			if route != test.expected {
+				t.Fatalf("Expected route %v, got %v", test.expected, route)
+			}
+		})
+	}
+}
+
+func TestTreeInvalidation(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var parent = m.Handle(mux.GET, "/parent/", h)
+
+	if route, _ := m.Match(mux.GET, "/parent/child/"); route != nil {
+		t.Fatalf("Expected no route, got %v", route)
+	}
+
+	var child = parent.Handle(mux.GET, "/child/", h)
+	if route, _ := m.Match(mux.GET, "/parent/child/"); route != child {
+		t.Fatalf("Expected %v, got %v", child, route)
+	}
+
+	parent.RemoveChild(child)
+	if route, _ := m.Match(mux.GET, "/parent/child/"); route != nil {
+		t.Fatalf("Expected no route after removal, got %v", route)
+	}
+
+	m.RemoveRoute(parent)
+	if route, _ := m.Match(mux.GET, "/parent/"); route != nil {
+		t.Fatalf("Expected no route after removal, got %v", route)
+	}
+}
+
+func BenchmarkMatchScaling(b *testing.B) {
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	for _, n := range []int{10, 100, 1000} {
+		var m = mux.New()
+		for i := 0; i < n; i++ {
+			m.Handle(mux.GET, fmt.Sprintf("/resource%d/<<id>>/items/<<item>>/", i), h)
+		}
+
+		var first = "/resource0/1/items/2/"
+		var last = fmt.Sprintf("/resource%d/1/items/2/", n-1)
+		for _, path := range []string{first, last} {
+			b.Run(fmt.Sprintf("routes-%d%s", n, path), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if route, _ := m.Match(mux.GET, path); route == nil {
+						b.Fatalf("Expected a match for %s", path)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestUnreachableRoutes(t *testing.T) {
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var expectPanic = func(t *testing.T, register func(m *mux.Mux)) {
+		t.Helper()
+		var m = mux.New()
+		m.StrictRoutes = true
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected a panic for an unreachable route")
+			}
+		}()
+		register(m)
+	}
+
+	t.Run("SamePattern", func(t *testing.T) {
+		expectPanic(t, func(m *mux.Mux) {
+			m.Handle(mux.GET, "/users/<<id>>/", h)
+			m.Handle(mux.GET, "/users/<<name>>/", h)
+		})
+	})
+
+	t.Run("ShadowedByAny", func(t *testing.T) {
+		expectPanic(t, func(m *mux.Mux) {
+			m.Handle(mux.ANY, "/users/", h)
+			m.Handle(mux.POST, "/users/", h)
+		})
+	})
+
+	t.Run("NestedChild", func(t *testing.T) {
+		expectPanic(t, func(m *mux.Mux) {
+			m.Handle(mux.GET, "/users/<<id>>/posts/", h)
+			m.Handle(mux.GET, "/users/", nil).Handle(mux.GET, "/<<pk>>/posts/", h)
+		})
+	})
+
+	t.Run("ChildShadowsLaterRoute", func(t *testing.T) {
+		expectPanic(t, func(m *mux.Mux) {
+			var users = m.Handle(mux.ANY, "/users/", nil)
+			m.Handle(mux.GET, "/users/<<id>>/", h)
+			m.Handle(mux.GET, "/about/", h)
+			users.Handle(mux.GET, "/<<pk>>/", h)
+		})
+	})
+
+	t.Run("NotAdded", func(t *testing.T) {
+		var m = mux.New()
+		m.StrictRoutes = true
+		m.Handle(mux.GET, "/users/", h)
+		expectPanic(t, func(*mux.Mux) {
+			m.Handle(mux.GET, "/users/", h)
+		})
+		if routes := m.Routes(); len(routes) != 1 {
+			t.Fatalf("Expected the unreachable route not to be added, got %d routes", len(routes))
+		}
+	})
+
+	t.Run("HostChanged", func(t *testing.T) {
+		expectPanic(t, func(m *mux.Mux) {
+			m.AddRoute(mux.NewRoute(mux.GET, "/users/", h).WithHost("example.com"))
+			var other = mux.NewRoute(mux.GET, "/users/", h).WithHost("other.com")
+			m.AddRoute(other)
+			m.Handle(mux.GET, "/about/", h)
+			other.WithHost("example.com")
+		})
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		var m = mux.New()
+		m.StrictRoutes = true
+		var rt = m.Handle(mux.GET, "/users/<<id>>/", h)
+		m.Handle(mux.GET, "/about/", h)
+		m.RemoveRoute(rt)
+		m.Handle(mux.GET, "/users/<<pk>>/", h)
+	})
+
+	t.Run("Reachable", func(t *testing.T) {
+		var m = mux.New()
+		m.StrictRoutes = true
+		m.Handle(mux.GET, "/users/<<id>>/", h)
+		m.Handle(mux.GET, "/users/<<id:int>>/", h)
+		m.Handle(mux.POST, "/users/<<id>>/", h)
+		m.Handle(mux.ANY, "/users/<<id>>/", h)
+		m.Handle(mux.GET, "/users/me/", h)
+		m.Handle(mux.GET, "/users/", nil)
+		m.Handle(mux.GET, "/users/", h)
+	})
+}
+
+func BenchmarkHandle(b *testing.B) {
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	for i := 0; i < b.N; i++ {
+		var m = mux.New()
+		for j := 0; j < 5000; j++ {
+			m.Handle(mux.GET, fmt.Sprintf("/route%d/<<id>>/", j), h)
+		}
+		m.Compile()
+	}
+}
@@ -0,0 +1,157 @@
+//go:build !js && !wasm
+// +build !js,!wasm
+
+package mux
+
+import (
+	"maps"
+	"net/http"
+)
+
+// Tx changes the routes of a mux as a single update, see Mux.Update.
+type Tx struct {
+	mux *Mux
+
+	// The state of the mux before the update, restored if it fails.
+	routes   []*Route
+	children map[*Route][]*Route
+
+	// The routes added by the update.
+	staged []*Route
+}
+
+// Update calls fn to change the routes of the mux as a single update.
+//
+// Requests are served by the routes from before the update until fn returns,
+// after which all changes are published at once. If fn panics, the changes
+// are rolled back and the panic is propagated.
+//
+// Updates are serialized with each other and with the other methods which change the routes.
+// Within fn, routes must only be changed through tx and through the routes it added,
+// changing other routes or calling methods like Walk deadlocks.
+// Lookups like Find and Match see the routes from before the update.
+func (r *Mux) Update(fn func(tx *Tx)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Lookups during the update are served from the current snapshots.
+	r.compile()
+	r.publishNames()
+
+	var tx = &Tx{
+		mux:      r,
+		routes:   r.routes,
+		children: make(map[*Route][]*Route),
+	}
+	r.tx = tx
+
+	var committed bool
+	defer func() {
+		r.tx = nil
+		for _, rt := range tx.staged {
+			unstage(rt)
+		}
+		if !committed {
+			tx.rollback()
+		}
+	}()
+
+	fn(tx)
+
+	// Build the snapshots before publishing them, so they
+	// are swapped at once and a panic rolls the update back.
+	var tree = buildTree(r.routes, r.middleware)
+	var names = maps.Clone(r.buildNames())
+
+	r.tree.Store(tree)
+	r.names.Store(&names)
+	committed = true
+}
+
+// Handle adds a handler to the mux, see Mux.Handle.
+func (tx *Tx) Handle(method string, path string, handler Handler, name ...string) *Route {
+	var rt = tx.mux.handle(method, path, handler, name...)
+	tx.stage(rt)
+	return rt
+}
+
+func (tx *Tx) HandleFunc(method string, path string, handler func(w http.ResponseWriter, r *http.Request), name ...string) *Route {
+	return tx.Handle(method, path, NewHandler(handler), name...)
+}
+
+// AddRoute adds the route to the mux, see Mux.AddRoute.
+func (tx *Tx) AddRoute(rt *Route) {
+	tx.mux.addRoute(rt)
+	tx.stage(rt)
+}
+
+// AddChild adds the route as a child of the parent, which may be a route added before the update.
+func (tx *Tx) AddChild(parent *Route, rt *Route) {
+	parent.addRoute(rt)
+	tx.stage(rt)
+}
+
+// RemoveRoute removes the top-level route from the mux.
+func (tx *Tx) RemoveRoute(rt *Route) {
+	tx.mux.removeRoute(rt)
+}
+
+// RemoveChild removes the child from the parent, which may be a route added before the update.
+func (tx *Tx) RemoveChild(parent *Route, child *Route) {
+	parent.removeChild(child)
+}
+
+// RemoveByPath removes the route with the path from the mux, see Mux.RemoveByPath.
+func (tx *Tx) RemoveByPath(path string) {
+	tx.mux.removeByPath(path)
+}
+
+// ResetRoutes removes all routes from the mux.
+func (tx *Tx) ResetRoutes() {
+	tx.mux.resetRoutes()
+}
+
+// Find returns the route with the fully qualified name, including the routes added by the update.
+func (tx *Tx) Find(name string) *Route {
+	return tx.mux.buildNames()[name]
+}
+
+// stage marks the route and its children as added by the update.
+func (tx *Tx) stage(rt *Route) {
+	if rt.staged {
+		return
+	}
+	rt.staged = true
+	tx.staged = append(tx.staged, rt)
+	for _, child := range rt.Children {
+		tx.stage(child)
+	}
+}
+
+func unstage(rt *Route) {
+	rt.staged = false
+	for _, child := range rt.Children {
+		unstage(child)
+	}
+}
+
+// save remembers the children of a route from before the update, so they can be restored.
+func (tx *Tx) save(rt *Route) {
+	if _, ok := tx.children[rt]; ok || rt.staged {
+		return
+	}
+	tx.children[rt] = rt.Children
+}
+
+// rollback restores the routes of the mux to the state before the update.
+//
+// The published snapshots were not replaced, they still describe this state.
+func (tx *Tx) rollback() {
+	var r = tx.mux
+	r.routes = tx.routes
+	r.nameIdx = nil
+	r.resetReachable()
+	for rt, children := range tx.children {
+		rt.Children = children
+	}
+}
@@ -0,0 +1,177 @@
+package mux_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Nigel2392/mux"
+)
+
+func serveStatus(m *mux.Mux, path string) int {
+	var rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(mux.GET, path, nil))
+	return rec.Code
+}
+
+func TestConcurrentRegistration(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var api = m.Handle(mux.ANY, "/api/", nil, "api")
+	m.Handle(mux.GET, "/", h, "index")
+
+	var wg sync.WaitGroup
+	var done = make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if serveStatus(m, "/") != http.StatusOK {
+					t.Error("Expected the index to be served while routes change")
+					return
+				}
+				serveStatus(m, "/api/plugin/1/")
+				serveStatus(m, "/matched/1/")
+				m.Find("api:plugin")
+				m.Routes()
+			}
+		}()
+	}
+
+	var mw = func(next mux.Handler) mux.Handler { return next }
+	var matched = m.Handle(mux.GET, "/matched/<<id:int>>/", h)
+	for i := 0; i < 200; i++ {
+		var plugin = api.Handle(mux.GET, fmt.Sprintf("/plugin/%d/", i), h, "plugin")
+		var top = m.Handle(mux.GET, fmt.Sprintf("/top/%d/", i), h)
+
+		// Changing routes which are being matched.
+		matched.Headers("X-Plugin", "")
+		matched.WithHost(fmt.Sprintf("<<sub>>.example%d.com", i))
+		matched.Use(mw)
+		m.Use(mw)
+		if i%2 == 0 {
+			api.RemoveChild(plugin)
+			m.RemoveRoute(top)
+		} else {
+			m.RemoveByPath(fmt.Sprintf("/top/%d/", i))
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if serveStatus(m, "/api/plugin/1/") != http.StatusOK || serveStatus(m, "/api/plugin/2/") != http.StatusNotFound {
+		t.Fatal("Expected only the plugin routes which were not removed to be served")
+	}
+}
+
+func TestInFlightRequestsKeepRoutes(t *testing.T) {
+	var m = mux.New()
+	var started, release = make(chan struct{}), make(chan struct{})
+	var route = m.HandleFunc(mux.GET, "/slow/", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		if mux.RouteFromContext(r.Context()).Name != "slow" {
+			t.Error("Expected the route to stay available to the request")
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}, "slow")
+
+	var code = make(chan int)
+	go func() { code <- serveStatus(m, "/slow/") }()
+
+	<-started
+	m.RemoveRoute(route)
+	close(release)
+
+	if c := <-code; c != http.StatusAccepted {
+		t.Fatalf("Expected the request in flight to finish, got %d", c)
+	}
+	if c := serveStatus(m, "/slow/"); c != http.StatusNotFound {
+		t.Fatalf("Expected the route to be removed for new requests, got %d", c)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	var m = mux.New()
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var api = m.Handle(mux.ANY, "/api/", nil, "api")
+	api.Handle(mux.GET, "/old/", h, "old")
+
+	m.Update(func(tx *mux.Tx) {
+		var users = tx.Handle(mux.ANY, "/users/", nil, "users")
+		users.Handle(mux.GET, "/<<id:int>>/", h, "detail")
+		tx.AddChild(api, mux.NewRoute(mux.GET, "/new/", h, "new"))
+		tx.RemoveByPath("/api/old/")
+
+		// Not visible until the update is committed.
+		if serveStatus(m, "/users/1/") != http.StatusNotFound || m.Find("users:detail") != nil {
+			t.Error("Expected the update to be invisible until it is committed")
+		}
+		if serveStatus(m, "/api/old/") != http.StatusOK {
+			t.Error("Expected removed routes to be served until the update is committed")
+		}
+		if tx.Find("users:detail") == nil {
+			t.Error("Expected the transaction to find its own routes")
+		}
+	})
+
+	for path, code := range map[string]int{"/users/1/": 200, "/api/new/": 200, "/api/old/": 404} {
+		if c := serveStatus(m, path); c != code {
+			t.Fatalf("Expected %s to be %d, got %d", path, code, c)
+		}
+	}
+	if m.Find("users:detail") == nil || m.Find("api:new") == nil || m.Find("api:old") != nil {
+		t.Fatal("Expected the name index to be updated")
+	}
+
+	// Routes added by an update can be changed directly afterwards.
+	m.Find("users").Handle(mux.GET, "/me/", h, "me")
+	if serveStatus(m, "/users/me/") != http.StatusOK {
+		t.Fatal("Expected /users/me/ to be served")
+	}
+}
+
+func TestUpdateRollback(t *testing.T) {
+	var m = mux.New()
+	m.UniqueNames = true
+	var h = mux.NewHandler(func(w http.ResponseWriter, r *http.Request) {})
+	var api = m.Handle(mux.ANY, "/api/", nil, "api")
+	api.Handle(mux.GET, "/users/", h, "users")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected the duplicate name to panic")
+			}
+		}()
+		m.Update(func(tx *mux.Tx) {
+			tx.Handle(mux.GET, "/about/", h, "about")
+			tx.RemoveByPath("/api/users/")
+			tx.AddChild(api, mux.NewRoute(mux.GET, "/posts/", h, "posts"))
+			tx.Handle(mux.GET, "/info/", h, "about")
+		})
+	}()
+
+	for path, code := range map[string]int{"/about/": 404, "/api/users/": 200, "/api/posts/": 404} {
+		if c := serveStatus(m, path); c != code {
+			t.Fatalf("Expected %s to be %d after the rollback, got %d", path, code, c)
+		}
+	}
+	if m.Find("about") != nil || m.Find("api:users") == nil || len(m.Routes()) != 2 {
+		t.Fatalf("Expected the routes to be rolled back, got %v", m.Routes())
+	}
+
+	// The mux is still usable.
+	m.Handle(mux.GET, "/about/", h, "about")
+	if serveStatus(m, "/about/") != http.StatusOK {
+		t.Fatal("Expected /about/ to be served")
+	}
+}